@@ -19,11 +19,20 @@ package main
 
 import (
 	"context"
+	"helloworld/pkg/accesslog"
 	"helloworld/pkg/config"
+	"helloworld/pkg/idempotency"
 	"helloworld/pkg/instance"
+	"helloworld/pkg/log"
+	"helloworld/pkg/rpcctx"
+	"helloworld/pkg/tracing"
+	"strings"
 
+	"dubbo.apache.org/dubbo-go/v3/client"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	_ "dubbo.apache.org/dubbo-go/v3/imports"
 	"github.com/dubbogo/gost/log/logger"
+	"go.opentelemetry.io/otel"
 
 	"helloworld/greet"
 )
@@ -34,21 +43,36 @@ func main() {
 		logger.Errorf("parse config failed: %v", err)
 		panic(err)
 	}
-	logger.Debugf("Starting server with config: %+v", cfg)
+	logger.Debugf("Starting server with config: %+v", cfg.Redacted())
 
 	ins, err := instance.InitInstance(cfg)
 	if err != nil {
 		logger.Errorf("new dubbo instance failed: %v", err)
 		panic(err)
 	}
-	clients, err := config.InitializeClients(cfg.AppName, cfg.Nacos.Group)
+	config.SetHistoryOptions(cfg.History)
+	config.SetReloadOptions(cfg.Reload)
+	config.SetFormat(cfg.ConfigFormat)
+	config.SetDefaultLogLevel(cfg.LogLevel)
+	if cfg.ConfigFile != "" {
+		config.SetSource(config.NewFileSource(cfg.ConfigFile, 0))
+	}
+	clients, err := config.InitializeClients(cfg.AppName, cfg.Nacos.DataID, cfg.Nacos.Group)
 	if err != nil {
 		logger.Errorf("Failed to initialize some clients: %v", err)
 	}
 	defer config.CloseClients(clients)
 
+	// 初始化 RPC 访问日志
+	if logCfg, err := config.GetLogConfigFromNacos(); err == nil {
+		if err := accesslog.Setup(&logCfg.Access); err != nil {
+			logger.Errorf("Failed to init access log: %v", err)
+		}
+	}
+
 	// 创建 client
-	cli, err := ins.NewClient()
+	filters := []string{constant.OTELClientTraceKey, accesslog.ConsumerFilterKey}
+	cli, err := ins.NewClient(client.WithClientFilter(strings.Join(filters, ",")))
 	if err != nil {
 		logger.Errorf("new client failed: %v", err)
 		panic(err)
@@ -63,15 +87,47 @@ func main() {
 
 	// 调用服务
 	logger.Info("start to test dubbo")
+	// 开启根 span 并生成请求 ID，客户端和服务端日志可以通过 trace_id / request_id 关联
+	ctx, span := otel.Tracer(tracing.ScopeName).Start(context.Background(), "greet-client")
+	ctx = rpcctx.WithRequestID(ctx, rpcctx.NewRequestID())
+	ctx = rpcctx.WithAttachment(ctx, rpcctx.CallerAppKey, cfg.AppName)
 	req := &greet.GreetRequest{
 		Name: "laurence",
 	}
-	reply, err := greeterClient.Greet(context.Background(), req)
+	// 携带幂等键，重试时服务端只会落库一次
+	greetCtx := rpcctx.WithAttachment(ctx, idempotency.AttachmentKey, idempotency.NewKey())
+	reply, err := greeterClient.Greet(greetCtx, req)
 	if err != nil {
-		logger.Errorf("call SayHello failed: %v", err)
+		log.FromContext(ctx).Errorf("call SayHello failed: %v", err)
 		panic(err)
 	}
-	logger.Infof("client response result: %v\n", reply)
+	log.FromContext(ctx).Infof("client response result: %v\n", reply)
+
+	// 使用相同幂等键重试，服务端直接回放第一次的响应
+	if _, err := greeterClient.Greet(greetCtx, req); err != nil {
+		log.FromContext(ctx).Errorf("retry SayHello failed: %v", err)
+	}
+
+	// 查询问候记录和统计
+	listReply, err := greeterClient.ListGreetings(ctx, &greet.ListGreetingsRequest{
+		Name:     req.Name,
+		PageSize: 10,
+	})
+	if err != nil {
+		log.FromContext(ctx).Errorf("call ListGreetings failed: %v", err)
+	} else {
+		log.FromContext(ctx).Infof("list greetings result: total=%d, page=%d, size=%d",
+			listReply.Total, listReply.Page, len(listReply.Greetings))
+	}
+
+	statsReply, err := greeterClient.GetGreetingStats(ctx, &greet.GetGreetingStatsRequest{})
+	if err != nil {
+		log.FromContext(ctx).Errorf("call GetGreetingStats failed: %v", err)
+	} else {
+		log.FromContext(ctx).Infof("greeting stats result: %v", statsReply)
+	}
+
+	span.End()
 
 	// 保持程序运行
 	select {}
@@ -19,29 +19,152 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	greet "helloworld/greet"
+	"helloworld/pkg/accesslog"
+	"helloworld/pkg/admin"
+	"helloworld/pkg/audit"
+	"helloworld/pkg/cache"
 	config "helloworld/pkg/config"
+	"helloworld/pkg/flags"
+	greetdomain "helloworld/pkg/greet"
+	"helloworld/pkg/idempotency"
 	"helloworld/pkg/instance"
+	"helloworld/pkg/log"
+	"helloworld/pkg/migrate"
+	"helloworld/pkg/ratelimit"
+	"helloworld/pkg/rpcctx"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	_ "dubbo.apache.org/dubbo-go/v3/imports"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"dubbo.apache.org/dubbo-go/v3/server"
 	"github.com/dubbogo/gost/log/logger"
+	"github.com/redis/go-redis/v9"
 )
 
-type GreetTripleServer struct{}
+// errRepoUnavailable MySQL 未初始化时返回
+var errRepoUnavailable = triple_protocol.NewError(triple_protocol.CodeUnavailable,
+	errors.New("greeting repository is unavailable"))
+
+// 缓存名称，对应 cache.ttls 下的配置项
+const (
+	cacheGreetList  = "greet_list"
+	cacheGreetStats = "greet_stats"
+)
+
+// cacheNamespaceGreet 问候列表和统计缓存的命名空间，写入问候记录后整体失效
+const cacheNamespaceGreet = "greet"
+
+type GreetTripleServer struct {
+	repo     greetdomain.Repository // MySQL 未初始化时为 nil
+	cache    *cache.Cache           // 未启用缓存时为 nil，直接查库
+	cacheCfg atomic.Pointer[config.CacheConfig]
+}
 
 func (srv *GreetTripleServer) Greet(ctx context.Context, req *greet.GreetRequest) (*greet.GreetResponse, error) {
-	logger.Infof("dobbo-do-service receive: %v", req)
+	if srv.repo != nil {
+		record := &greetdomain.Greeting{
+			Name:      req.Name,
+			CallerApp: rpcctx.CallerApp(ctx),
+			TraceID:   rpcctx.TraceID(ctx),
+		}
+		if err := srv.repo.Create(ctx, record); err != nil {
+			log.FromContext(ctx).Errorf("save greeting failed: %v", err)
+			return nil, triple_protocol.NewError(triple_protocol.CodeInternal, err)
+		}
+		// 列表和统计的查询条件组合太多，无法逐个删除，递增命名空间版本号使其全部失效
+		if err := srv.cache.Invalidate(ctx, cacheNamespaceGreet); err != nil {
+			log.FromContext(ctx).Warnf("invalidate greeting cache failed, stale for up to ttl: %v", err)
+		}
+	}
+
 	resp := &greet.GreetResponse{Greeting: req.Name}
 	return resp, nil
 }
 
+func (srv *GreetTripleServer) ListGreetings(ctx context.Context, req *greet.ListGreetingsRequest) (*greet.ListGreetingsResponse, error) {
+	if srv.repo == nil {
+		return nil, errRepoUnavailable
+	}
+
+	filter := greetdomain.FilterFromList(req)
+	key := fmt.Sprintf("list:%s:%d:%d:%d:%d", url.QueryEscape(filter.Name),
+		req.GetStartTime(), req.GetEndTime(), filter.Page, filter.PageSize)
+
+	resp, err := cache.GetOrLoad(ctx, srv.cache, key, func(ctx context.Context) (*greet.ListGreetingsResponse, error) {
+		records, total, err := srv.repo.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &greet.ListGreetingsResponse{
+			Greetings: make([]*greet.Greeting, 0, len(records)),
+			Total:     total,
+			Page:      int32(filter.Page),
+			PageSize:  int32(filter.PageSize),
+		}
+		for i := range records {
+			resp.Greetings = append(resp.Greetings, records[i].ToProto())
+		}
+		return resp, nil
+	}, srv.cacheOptions(cacheGreetList)...)
+	if err != nil {
+		log.FromContext(ctx).Errorf("list greetings failed: %v", err)
+		return nil, triple_protocol.NewError(triple_protocol.CodeInternal, err)
+	}
+	return resp, nil
+}
+
+func (srv *GreetTripleServer) GetGreetingStats(ctx context.Context, req *greet.GetGreetingStatsRequest) (*greet.GetGreetingStatsResponse, error) {
+	if srv.repo == nil {
+		return nil, errRepoUnavailable
+	}
+
+	filter := greetdomain.FilterFromStats(req)
+	key := fmt.Sprintf("stats:%s:%d:%d", url.QueryEscape(filter.Name), req.GetStartTime(), req.GetEndTime())
+
+	resp, err := cache.GetOrLoad(ctx, srv.cache, key, func(ctx context.Context) (*greet.GetGreetingStatsResponse, error) {
+		stats, err := srv.repo.Stats(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return stats.ToProto(), nil
+	}, srv.cacheOptions(cacheGreetStats)...)
+	if err != nil {
+		log.FromContext(ctx).Errorf("get greeting stats failed: %v", err)
+		return nil, triple_protocol.NewError(triple_protocol.CodeInternal, err)
+	}
+	return resp, nil
+}
+
+// cacheOptions 返回指定缓存名称的调用选项
+func (srv *GreetTripleServer) cacheOptions(name string) []cache.Option {
+	opts := []cache.Option{cache.WithCodec(cache.ProtoCodec), cache.WithNamespace(cacheNamespaceGreet)}
+	if cfg := srv.cacheCfg.Load(); cfg != nil {
+		opts = append(opts, cache.WithTTL(cfg.TTLFor(name)))
+	}
+	return opts
+}
+
 func main() {
+	// 子命令：发布前校验配置，如 server check-config -file app.yaml
+	if len(os.Args) > 1 && os.Args[1] == config.CheckConfigCommand {
+		os.Exit(config.RunCheckConfig(os.Args[2:], os.Stdout))
+	}
+
 	cfg, err := config.ParseConfig()
 	if err != nil {
 		logger.Errorf("parse config failed: %v", err)
 		panic(err)
 	}
-	logger.Debugf("Starting server with config: %+v", cfg)
+	logger.Debugf("Starting server with config: %+v", cfg.Redacted())
 
 	ins, err := instance.InitInstance(cfg)
 	if err != nil {
@@ -49,21 +172,125 @@ func main() {
 		panic(err)
 	}
 
-	clients, err := config.InitializeClients(cfg.AppName, cfg.Nacos.Group)
+	// 配置历史需要在加载应用配置之前设置，重启后可恢复固定的版本
+	config.SetHistoryOptions(cfg.History)
+	config.SetReloadOptions(cfg.Reload)
+	config.SetFormat(cfg.ConfigFormat)
+	config.SetDefaultLogLevel(cfg.LogLevel)
+	if cfg.ConfigFile != "" {
+		config.SetSource(config.NewFileSource(cfg.ConfigFile, 0))
+	}
+
+	// 配置变更审计需要在加载应用配置之前初始化，以记录启动时的配置
+	if err := audit.Setup(cfg.AppName, cfg.Audit); err != nil {
+		logger.Errorf("Failed to init audit log: %v", err)
+	}
+	defer audit.Close(5 * time.Second)
+
+	// 管理端：配置历史、回滚、固定版本
+	admin.AddStatus("app", func() interface{} {
+		return map[string]interface{}{
+			"name":        cfg.AppName,
+			"port":        cfg.AppPort,
+			"nacos":       cfg.Nacos,
+			"config_file": cfg.ConfigFile,
+		}
+	})
+	adminSrv, err := admin.Start(cfg.AdminAddr, admin.WithToken(cfg.AdminToken))
+	if err != nil {
+		logger.Errorf("Failed to start admin server: %v", err)
+	}
+	defer adminSrv.Close()
+
+	clients, err := config.InitializeClients(cfg.AppName, cfg.Nacos.DataID, cfg.Nacos.Group)
 	if err != nil {
 		logger.Errorf("Failed to initialize some clients: %v", err)
 	}
 	defer config.CloseClients(clients)
 
+	// 初始化 RPC 访问日志
+	if logCfg, err := config.GetLogConfigFromNacos(); err == nil {
+		if err := accesslog.Setup(&logCfg.Access); err != nil {
+			logger.Errorf("Failed to init access log: %v", err)
+		}
+	}
+
+	var redisClient *redis.Client
+	if clients != nil {
+		redisClient = clients.Redis
+	}
+
+	// 定期检查 Redis、MySQL，状态变化时记录审计事件并发送 webhook
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if redisClient != nil {
+		audit.Watch(watchCtx, "redis", 10*time.Second, func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		})
+	}
+	if clients != nil && clients.MySQL != nil {
+		if sqlDB, err := clients.MySQL.DB(); err == nil {
+			audit.Watch(watchCtx, "mysql", 10*time.Second, sqlDB.PingContext)
+		}
+	}
+
+	// 执行数据库迁移并创建仓储
+	handler := &GreetTripleServer{}
+	if clients != nil && clients.MySQL != nil {
+		if err := migrate.Run(context.Background(), clients.MySQL, greetdomain.Migrations()); err != nil {
+			logger.Errorf("run migrations failed: %v", err)
+			panic(err)
+		}
+		handler.repo = greetdomain.NewRepository(clients.MySQL)
+	}
+
+	// 创建缓存，Redis 不可用时自动回源数据库
+	// ttl、ttls、negative_ttl、jitter 支持热更新；enabled 和 prefix 修改后需要重启
+	if cacheCfg := config.GetCacheConfigFromDubbo(); cacheCfg.Enabled {
+		handler.cacheCfg.Store(cacheCfg)
+		handler.cache = cache.New(redisClient, cache.Options{
+			Prefix:      cacheCfg.Prefix,
+			TTL:         cacheCfg.TTL,
+			Jitter:      cacheCfg.Jitter,
+			NegativeTTL: cacheCfg.NegativeTTL,
+		})
+		logger.Infof("Cache enabled: redis=%v, ttl=%v, jitter=%.2f", redisClient != nil, cacheCfg.TTL, cacheCfg.Jitter)
+
+		config.RegisterChangeListener(func(map[string]interface{}) {
+			next := config.GetCacheConfigFromDubbo()
+			if next.Enabled != cacheCfg.Enabled || next.Prefix != cacheCfg.Prefix {
+				logger.Warnf("cache.enabled/cache.prefix changed, restart to apply")
+			}
+			handler.cacheCfg.Store(next)
+			handler.cache.UpdateTTL(next.TTL, next.NegativeTTL, next.Jitter)
+			logger.Infof("Cache config reloaded: ttl=%v, negative_ttl=%v, jitter=%.2f", next.TTL, next.NegativeTTL, next.Jitter)
+		})
+	}
+
+	// 初始化限流，Redis 不可用时使用进程内限流
+	ratelimit.Setup(redisClient)
+
+	// 初始化幂等，携带 idempotency-key 的重复请求只执行一次（如 Greet 只落库一次）
+	idempotency.Setup(redisClient, idempotency.Options{Prefix: "helloworld:idem:"})
+
+	// 加载功能开关，管理端 /status 中列出当前定义的开关
+	flags.Setup()
+	admin.AddStatus("flags", func() interface{} { return flags.Names() })
+
+	// 构建信息和配置 hash 写入 Nacos 实例元数据，配置更新后自动刷新
+	instance.PublishMetadata()
+
 	// 创建 server
-	srv, err := ins.NewServer()
+	// otel filter 放在最外层，后续 filter 和业务代码都能拿到 span
+	filters := []string{constant.OTELServerTraceKey, accesslog.ProviderFilterKey, ratelimit.FilterKey, idempotency.FilterKey, flags.FilterKey}
+	srv, err := ins.NewServer(server.WithServerFilter(strings.Join(filters, ",")))
 	if err != nil {
 		logger.Errorf("new server failed: %v", err)
 		panic(err)
 	}
 
 	// 注册服务（使用 V2 接口）
-	if err := greet.RegisterGreetServiceHandler(srv, &GreetTripleServer{}); err != nil {
+	if err := greet.RegisterGreetServiceHandler(srv, handler); err != nil {
 		logger.Errorf("register greeter v2 handler failed: %v", err)
 		panic(err)
 	}
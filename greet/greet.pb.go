@@ -125,6 +125,414 @@ func (x *GreetResponse) GetGreeting() string {
 	return ""
 }
 
+type Greeting struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CallerApp     string                 `protobuf:"bytes,3,opt,name=caller_app,json=callerApp,proto3" json:"caller_app,omitempty"`
+	TraceId       string                 `protobuf:"bytes,4,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Greeting) Reset() {
+	*x = Greeting{}
+	mi := &file_greet_greet_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Greeting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Greeting) ProtoMessage() {}
+
+func (x *Greeting) ProtoReflect() protoreflect.Message {
+	mi := &file_greet_greet_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Greeting.ProtoReflect.Descriptor instead.
+func (*Greeting) Descriptor() ([]byte, []int) {
+	return file_greet_greet_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Greeting) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Greeting) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Greeting) GetCallerApp() string {
+	if x != nil {
+		return x.CallerApp
+	}
+	return ""
+}
+
+func (x *Greeting) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *Greeting) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ListGreetingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	StartTime     int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGreetingsRequest) Reset() {
+	*x = ListGreetingsRequest{}
+	mi := &file_greet_greet_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGreetingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGreetingsRequest) ProtoMessage() {}
+
+func (x *ListGreetingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_greet_greet_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGreetingsRequest.ProtoReflect.Descriptor instead.
+func (*ListGreetingsRequest) Descriptor() ([]byte, []int) {
+	return file_greet_greet_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListGreetingsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListGreetingsRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *ListGreetingsRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *ListGreetingsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListGreetingsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListGreetingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Greetings     []*Greeting            `protobuf:"bytes,1,rep,name=greetings,proto3" json:"greetings,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGreetingsResponse) Reset() {
+	*x = ListGreetingsResponse{}
+	mi := &file_greet_greet_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGreetingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGreetingsResponse) ProtoMessage() {}
+
+func (x *ListGreetingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_greet_greet_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGreetingsResponse.ProtoReflect.Descriptor instead.
+func (*ListGreetingsResponse) Descriptor() ([]byte, []int) {
+	return file_greet_greet_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListGreetingsResponse) GetGreetings() []*Greeting {
+	if x != nil {
+		return x.Greetings
+	}
+	return nil
+}
+
+func (x *ListGreetingsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListGreetingsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListGreetingsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type GetGreetingStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	StartTime     int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGreetingStatsRequest) Reset() {
+	*x = GetGreetingStatsRequest{}
+	mi := &file_greet_greet_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGreetingStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGreetingStatsRequest) ProtoMessage() {}
+
+func (x *GetGreetingStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_greet_greet_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGreetingStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetGreetingStatsRequest) Descriptor() ([]byte, []int) {
+	return file_greet_greet_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetGreetingStatsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetGreetingStatsRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GetGreetingStatsRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+type NameCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NameCount) Reset() {
+	*x = NameCount{}
+	mi := &file_greet_greet_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NameCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NameCount) ProtoMessage() {}
+
+func (x *NameCount) ProtoReflect() protoreflect.Message {
+	mi := &file_greet_greet_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NameCount.ProtoReflect.Descriptor instead.
+func (*NameCount) Descriptor() ([]byte, []int) {
+	return file_greet_greet_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NameCount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NameCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetGreetingStatsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Total          int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	UniqueNames    int64                  `protobuf:"varint,2,opt,name=unique_names,json=uniqueNames,proto3" json:"unique_names,omitempty"`
+	FirstGreetedAt int64                  `protobuf:"varint,3,opt,name=first_greeted_at,json=firstGreetedAt,proto3" json:"first_greeted_at,omitempty"`
+	LastGreetedAt  int64                  `protobuf:"varint,4,opt,name=last_greeted_at,json=lastGreetedAt,proto3" json:"last_greeted_at,omitempty"`
+	TopNames       []*NameCount           `protobuf:"bytes,5,rep,name=top_names,json=topNames,proto3" json:"top_names,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetGreetingStatsResponse) Reset() {
+	*x = GetGreetingStatsResponse{}
+	mi := &file_greet_greet_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGreetingStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGreetingStatsResponse) ProtoMessage() {}
+
+func (x *GetGreetingStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_greet_greet_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGreetingStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetGreetingStatsResponse) Descriptor() ([]byte, []int) {
+	return file_greet_greet_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetGreetingStatsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetGreetingStatsResponse) GetUniqueNames() int64 {
+	if x != nil {
+		return x.UniqueNames
+	}
+	return 0
+}
+
+func (x *GetGreetingStatsResponse) GetFirstGreetedAt() int64 {
+	if x != nil {
+		return x.FirstGreetedAt
+	}
+	return 0
+}
+
+func (x *GetGreetingStatsResponse) GetLastGreetedAt() int64 {
+	if x != nil {
+		return x.LastGreetedAt
+	}
+	return 0
+}
+
+func (x *GetGreetingStatsResponse) GetTopNames() []*NameCount {
+	if x != nil {
+		return x.TopNames
+	}
+	return nil
+}
+
 var File_greet_greet_proto protoreflect.FileDescriptor
 
 const file_greet_greet_proto_rawDesc = "" +
@@ -133,9 +541,45 @@ const file_greet_greet_proto_rawDesc = "" +
 	"\fGreetRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\"+\n" +
 	"\rGreetResponse\x12\x1a\n" +
-	"\bgreeting\x18\x01 \x01(\tR\bgreeting2D\n" +
+	"\bgreeting\x18\x01 \x01(\tR\bgreeting\"\x87\x01\n" +
+	"\bGreeting\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"caller_app\x18\x03 \x01(\tR\tcallerApp\x12\x19\n" +
+	"\btrace_id\x18\x04 \x01(\tR\atraceId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\x03R\tcreatedAt\"\x95\x01\n" +
+	"\x14ListGreetingsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x03 \x01(\x03R\aendTime\x12\x12\n" +
+	"\x04page\x18\x04 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\"\x8d\x01\n" +
+	"\x15ListGreetingsResponse\x12-\n" +
+	"\tgreetings\x18\x01 \x03(\v2\x0f.greet.GreetingR\tgreetings\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"g\n" +
+	"\x17GetGreetingStatsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x03 \x01(\x03R\aendTime\"5\n" +
+	"\tNameCount\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xd4\x01\n" +
+	"\x18GetGreetingStatsResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x12!\n" +
+	"\funique_names\x18\x02 \x01(\x03R\vuniqueNames\x12(\n" +
+	"\x10first_greeted_at\x18\x03 \x01(\x03R\x0efirstGreetedAt\x12&\n" +
+	"\x0flast_greeted_at\x18\x04 \x01(\x03R\rlastGreetedAt\x12-\n" +
+	"\ttop_names\x18\x05 \x03(\v2\x10.greet.NameCountR\btopNames2\xe9\x01\n" +
 	"\fGreetService\x124\n" +
-	"\x05Greet\x12\x13.greet.GreetRequest\x1a\x14.greet.GreetResponse\"\x00B\x14Z\x12./helloworld;greetb\x06proto3"
+	"\x05Greet\x12\x13.greet.GreetRequest\x1a\x14.greet.GreetResponse\"\x00\x12L\n" +
+	"\rListGreetings\x12\x1b.greet.ListGreetingsRequest\x1a\x1c.greet.ListGreetingsResponse\"\x00\x12U\n" +
+	"\x10GetGreetingStats\x12\x1e.greet.GetGreetingStatsRequest\x1a\x1f.greet.GetGreetingStatsResponse\"\x00B\x14Z\x12./helloworld;greetb\x06proto3"
 
 var (
 	file_greet_greet_proto_rawDescOnce sync.Once
@@ -149,19 +593,31 @@ func file_greet_greet_proto_rawDescGZIP() []byte {
 	return file_greet_greet_proto_rawDescData
 }
 
-var file_greet_greet_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_greet_greet_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_greet_greet_proto_goTypes = []any{
-	(*GreetRequest)(nil),  // 0: greet.GreetRequest
-	(*GreetResponse)(nil), // 1: greet.GreetResponse
+	(*GreetRequest)(nil),             // 0: greet.GreetRequest
+	(*GreetResponse)(nil),            // 1: greet.GreetResponse
+	(*Greeting)(nil),                 // 2: greet.Greeting
+	(*ListGreetingsRequest)(nil),     // 3: greet.ListGreetingsRequest
+	(*ListGreetingsResponse)(nil),    // 4: greet.ListGreetingsResponse
+	(*GetGreetingStatsRequest)(nil),  // 5: greet.GetGreetingStatsRequest
+	(*NameCount)(nil),                // 6: greet.NameCount
+	(*GetGreetingStatsResponse)(nil), // 7: greet.GetGreetingStatsResponse
 }
 var file_greet_greet_proto_depIdxs = []int32{
-	0, // 0: greet.GreetService.Greet:input_type -> greet.GreetRequest
-	1, // 1: greet.GreetService.Greet:output_type -> greet.GreetResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: greet.ListGreetingsResponse.greetings:type_name -> greet.Greeting
+	6, // 1: greet.GetGreetingStatsResponse.top_names:type_name -> greet.NameCount
+	0, // 2: greet.GreetService.Greet:input_type -> greet.GreetRequest
+	3, // 3: greet.GreetService.ListGreetings:input_type -> greet.ListGreetingsRequest
+	5, // 4: greet.GreetService.GetGreetingStats:input_type -> greet.GetGreetingStatsRequest
+	1, // 5: greet.GreetService.Greet:output_type -> greet.GreetResponse
+	4, // 6: greet.GreetService.ListGreetings:output_type -> greet.ListGreetingsResponse
+	7, // 7: greet.GreetService.GetGreetingStats:output_type -> greet.GetGreetingStatsResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_greet_greet_proto_init() }
@@ -175,7 +631,7 @@ func file_greet_greet_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_greet_greet_proto_rawDesc), len(file_greet_greet_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
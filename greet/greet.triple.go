@@ -38,6 +38,10 @@ const (
 const (
 	// GreetServiceGreetProcedure is the fully-qualified name of the GreetService's Greet RPC.
 	GreetServiceGreetProcedure = "/greet.GreetService/Greet"
+	// GreetServiceListGreetingsProcedure is the fully-qualified name of the GreetService's ListGreetings RPC.
+	GreetServiceListGreetingsProcedure = "/greet.GreetService/ListGreetings"
+	// GreetServiceGetGreetingStatsProcedure is the fully-qualified name of the GreetService's GetGreetingStats RPC.
+	GreetServiceGetGreetingStatsProcedure = "/greet.GreetService/GetGreetingStats"
 )
 
 var (
@@ -47,6 +51,8 @@ var (
 // GreetService is a client for the greet.GreetService service.
 type GreetService interface {
 	Greet(ctx context.Context, req *GreetRequest, opts ...client.CallOption) (*GreetResponse, error)
+	ListGreetings(ctx context.Context, req *ListGreetingsRequest, opts ...client.CallOption) (*ListGreetingsResponse, error)
+	GetGreetingStats(ctx context.Context, req *GetGreetingStatsRequest, opts ...client.CallOption) (*GetGreetingStatsResponse, error)
 }
 
 // NewGreetService constructs a client for the greet.GreetService service.
@@ -77,9 +83,25 @@ func (c *GreetServiceImpl) Greet(ctx context.Context, req *GreetRequest, opts ..
 	return resp, nil
 }
 
+func (c *GreetServiceImpl) ListGreetings(ctx context.Context, req *ListGreetingsRequest, opts ...client.CallOption) (*ListGreetingsResponse, error) {
+	resp := new(ListGreetingsResponse)
+	if err := c.conn.CallUnary(ctx, []interface{}{req}, resp, "ListGreetings", opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *GreetServiceImpl) GetGreetingStats(ctx context.Context, req *GetGreetingStatsRequest, opts ...client.CallOption) (*GetGreetingStatsResponse, error) {
+	resp := new(GetGreetingStatsResponse)
+	if err := c.conn.CallUnary(ctx, []interface{}{req}, resp, "GetGreetingStats", opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 var GreetService_ClientInfo = client.ClientInfo{
 	InterfaceName: "greet.GreetService",
-	MethodNames:   []string{"Greet"},
+	MethodNames:   []string{"Greet", "ListGreetings", "GetGreetingStats"},
 	ConnectionInjectFunc: func(dubboCliRaw interface{}, conn *client.Connection) {
 		dubboCli := dubboCliRaw.(*GreetServiceImpl)
 		dubboCli.conn = conn
@@ -89,6 +111,8 @@ var GreetService_ClientInfo = client.ClientInfo{
 // GreetServiceHandler is an implementation of the greet.GreetService service.
 type GreetServiceHandler interface {
 	Greet(context.Context, *GreetRequest) (*GreetResponse, error)
+	ListGreetings(context.Context, *ListGreetingsRequest) (*ListGreetingsResponse, error)
+	GetGreetingStats(context.Context, *GetGreetingStatsRequest) (*GetGreetingStatsResponse, error)
 }
 
 func RegisterGreetServiceHandler(srv *server.Server, hdlr GreetServiceHandler, opts ...server.ServiceOption) error {
@@ -118,5 +142,35 @@ var GreetService_ServiceInfo = server.ServiceInfo{
 				return triple_protocol.NewResponse(res), nil
 			},
 		},
+		{
+			Name: "ListGreetings",
+			Type: constant.CallUnary,
+			ReqInitFunc: func() interface{} {
+				return new(ListGreetingsRequest)
+			},
+			MethodFunc: func(ctx context.Context, args []interface{}, handler interface{}) (interface{}, error) {
+				req := args[0].(*ListGreetingsRequest)
+				res, err := handler.(GreetServiceHandler).ListGreetings(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				return triple_protocol.NewResponse(res), nil
+			},
+		},
+		{
+			Name: "GetGreetingStats",
+			Type: constant.CallUnary,
+			ReqInitFunc: func() interface{} {
+				return new(GetGreetingStatsRequest)
+			},
+			MethodFunc: func(ctx context.Context, args []interface{}, handler interface{}) (interface{}, error) {
+				req := args[0].(*GetGreetingStatsRequest)
+				res, err := handler.(GreetServiceHandler).GetGreetingStats(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				return triple_protocol.NewResponse(res), nil
+			},
+		},
 	},
 }
@@ -0,0 +1,131 @@
+package accesslog
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"helloworld/pkg/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// state 访问日志的运行时状态，整体替换以支持重新初始化
+type state struct {
+	logger   *zap.Logger
+	sampling map[string]float64
+}
+
+var current atomic.Pointer[state]
+
+// Setup 初始化访问日志，未调用或 cfg.Enabled 为 false 时 filter 不输出日志
+func Setup(cfg *config.AccessLogConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("access log config is nil")
+	}
+	if !cfg.Enabled {
+		current.Store(nil)
+		return nil
+	}
+
+	var writer zapcore.WriteSyncer
+	if cfg.Filename != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.Filename), 0755); err != nil {
+			return fmt.Errorf("failed to create access log directory: %w", err)
+		}
+		writer = zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.Filename,
+			MaxSize:    cfg.MaxSize,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAge,
+			Compress:   true,
+			LocalTime:  true,
+		})
+	} else {
+		writer = zapcore.AddSync(os.Stdout)
+	}
+
+	encoderConfig := zapcore.EncoderConfig{
+		TimeKey:        "time",
+		MessageKey:     "msg",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.MillisDurationEncoder,
+	}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), writer, zapcore.InfoLevel)
+
+	sampling := make(map[string]float64, len(cfg.Sampling))
+	for k, v := range cfg.Sampling {
+		sampling[k] = v
+	}
+	current.Store(&state{logger: zap.New(core), sampling: sampling})
+	return nil
+}
+
+// Entry 一次 RPC 调用的访问日志
+type Entry struct {
+	Side      string // provider 或 consumer
+	Service   string
+	Method    string
+	Peer      string // 对端地址
+	CallerApp string
+	RequestID string
+	TraceID   string
+	Code      string // Triple 状态码，成功为 ok
+	Latency   int64  // 纳秒
+	ReqSize   int
+	RespSize  int
+	Err       error
+}
+
+// Log 输出访问日志，成功的请求按方法采样
+func Log(e *Entry) {
+	if s := current.Load(); s != nil && s.keep(e) {
+		s.write(e)
+	}
+}
+
+// keep 判断是否输出：失败的请求全部输出，成功的请求按方法采样
+func (s *state) keep(e *Entry) bool {
+	return e.Err != nil || s.sampled(e.Service, e.Method)
+}
+
+// write 输出访问日志，不再采样
+func (s *state) write(e *Entry) {
+	fields := []zap.Field{
+		zap.String("side", e.Side),
+		zap.String("service", e.Service),
+		zap.String("method", e.Method),
+		zap.String("peer", e.Peer),
+		zap.String("caller_app", e.CallerApp),
+		zap.String("request_id", e.RequestID),
+		zap.String("trace_id", e.TraceID),
+		zap.String("code", e.Code),
+		zap.Float64("latency_ms", float64(e.Latency)/1e6),
+		zap.Int("req_size", e.ReqSize),
+		zap.Int("resp_size", e.RespSize),
+	}
+	if e.Err != nil {
+		fields = append(fields, zap.String("error", e.Err.Error()))
+	}
+	s.logger.Info("rpc access", fields...)
+}
+
+// sampled 判断成功请求是否记录，未配置采样的方法全部记录
+func (s *state) sampled(service, method string) bool {
+	ratio, ok := s.sampling[service+"."+method]
+	if !ok {
+		ratio, ok = s.sampling[method]
+	}
+	if !ok || ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	return rand.Float64() < ratio
+}
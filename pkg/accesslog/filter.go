@@ -0,0 +1,164 @@
+package accesslog
+
+import (
+	"context"
+	"time"
+
+	"helloworld/pkg/rpcctx"
+
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	gxnet "github.com/dubbogo/gost/net"
+	"google.golang.org/protobuf/proto"
+)
+
+// filter 名称，分别通过 server.WithServerFilter / client.WithClientFilter 启用
+const (
+	ProviderFilterKey = "accesslog-provider"
+	ConsumerFilterKey = "accesslog-consumer"
+)
+
+// localIP 本机 IP，consumer 通过 caller-host 传给 provider 作为对端地址
+var localIP, _ = gxnet.GetLocalIP()
+
+func init() {
+	extension.SetFilter(ProviderFilterKey, func() filter.Filter { return &providerFilter{} })
+	extension.SetFilter(ConsumerFilterKey, func() filter.Filter { return &consumerFilter{} })
+}
+
+type providerFilter struct{}
+
+// Invoke 读取或生成请求 ID 放入 context，调用结束后输出访问日志
+func (f *providerFilter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	start := time.Now()
+
+	requestID := rpcctx.InvocationAttachment(invocation, rpcctx.RequestIDKey)
+	if requestID == "" {
+		requestID = rpcctx.NewRequestID()
+		invocation.SetAttachment(rpcctx.RequestIDKey, requestID)
+	}
+	ctx = rpcctx.WithRequestID(ctx, requestID)
+
+	res := invoker.Invoke(ctx, invocation)
+	res.AddAttachment(rpcctx.RequestIDKey, requestID)
+
+	peer := rpcctx.InvocationAttachment(invocation, constant.RemoteAddr)
+	if peer == "" {
+		peer = rpcctx.InvocationAttachment(invocation, rpcctx.CallerHostKey)
+	}
+
+	e := &Entry{
+		Side:      "provider",
+		Service:   invoker.GetURL().Service(),
+		Method:    invocation.MethodName(),
+		Peer:      peer,
+		CallerApp: rpcctx.InvocationAttachment(invocation, rpcctx.CallerAppKey),
+		RequestID: requestID,
+		TraceID:   rpcctx.TraceID(ctx),
+		Code:      codeOf(res.Error()),
+		Latency:   int64(time.Since(start)),
+		Err:       res.Error(),
+	}
+	// 消息大小需要重新序列化计算，只在确定输出后计算
+	if s := current.Load(); s != nil && s.keep(e) {
+		e.ReqSize = sizeOf(invocation.Arguments())
+		e.RespSize = sizeOf(res.Result())
+		s.write(e)
+	}
+	return res
+}
+
+// OnResponse 直接返回结果
+func (f *providerFilter) OnResponse(_ context.Context, result result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return result
+}
+
+type consumerFilter struct{}
+
+// Invoke 透传请求 ID、调用方应用名和 IP，调用结束后输出访问日志
+func (f *consumerFilter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	start := time.Now()
+	url := invoker.GetURL()
+
+	requestID := rpcctx.InvocationAttachment(invocation, rpcctx.RequestIDKey)
+	if requestID == "" {
+		requestID = rpcctx.RequestID(ctx)
+	}
+	if requestID == "" {
+		requestID = rpcctx.NewRequestID()
+	}
+	invocation.SetAttachment(rpcctx.RequestIDKey, requestID)
+
+	callerApp := rpcctx.InvocationAttachment(invocation, rpcctx.CallerAppKey)
+	if callerApp == "" {
+		callerApp = url.GetParam(constant.ApplicationKey, "")
+		if callerApp != "" {
+			invocation.SetAttachment(rpcctx.CallerAppKey, callerApp)
+		}
+	}
+	if localIP != "" {
+		invocation.SetAttachment(rpcctx.CallerHostKey, localIP)
+	}
+
+	res := invoker.Invoke(ctx, invocation)
+
+	e := &Entry{
+		Side:      "consumer",
+		Service:   url.Service(),
+		Method:    invocation.MethodName(),
+		Peer:      url.Location,
+		CallerApp: callerApp,
+		RequestID: requestID,
+		TraceID:   rpcctx.TraceID(ctx),
+		Code:      codeOf(res.Error()),
+		Latency:   int64(time.Since(start)),
+		Err:       res.Error(),
+	}
+	if s := current.Load(); s != nil && s.keep(e) {
+		// 非 IDL 模式下最后一个参数是响应
+		var reply interface{}
+		if raw := invocation.ParameterRawValues(); len(raw) > 1 {
+			reply = raw[len(raw)-1]
+		}
+		e.ReqSize = sizeOf(invocation.Arguments())
+		e.RespSize = sizeOf(reply)
+		s.write(e)
+	}
+	return res
+}
+
+// OnResponse 直接返回结果
+func (f *consumerFilter) OnResponse(_ context.Context, result result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return result
+}
+
+// codeOf 返回 Triple 状态码名称
+func codeOf(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return triple_protocol.CodeOf(err).String()
+}
+
+// sizeOf 计算 protobuf 消息序列化后的大小，非 protobuf 消息返回 0
+func sizeOf(v interface{}) int {
+	switch m := v.(type) {
+	case nil:
+		return 0
+	case proto.Message:
+		return proto.Size(m)
+	case triple_protocol.AnyResponse:
+		return sizeOf(m.Any())
+	case []interface{}:
+		size := 0
+		for _, item := range m {
+			size += sizeOf(item)
+		}
+		return size
+	}
+	return 0
+}
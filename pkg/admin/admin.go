@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"helloworld/pkg/log"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// logger 管理端日志
+var logger = log.Named("helloworld/pkg/admin")
+
+// mux 管理端路由，其他包通过 Handle 注册
+var mux = http.NewServeMux()
+
+func init() {
+	mux.Handle("GET /metrics", promhttp.Handler())
+}
+
+// Handle 注册管理端接口，pattern 使用 net/http 的路由语法，如 "GET /config/history"
+func Handle(pattern string, handler http.HandlerFunc) {
+	mux.HandleFunc(pattern, handler)
+}
+
+// Server 管理端 HTTP 服务，只用于运维操作，默认只监听本机地址
+type Server struct {
+	srv *http.Server
+	ln  net.Listener
+}
+
+// Option 管理端选项
+type Option func(o *options)
+
+type options struct {
+	token string
+}
+
+// WithToken 设置回滚、固定等接口的 token，为空时这些接口不可用
+func WithToken(t string) Option {
+	return func(o *options) {
+		o.token = t
+	}
+}
+
+// Start 启动管理端服务，addr 为空时不启动并返回 nil
+func Start(addr string, opts ...Option) (*Server, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	token.Store(&o.token)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		srv: &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		ln:  ln,
+	}
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("Admin server stopped: %v", err)
+		}
+	}()
+	logger.Infof("Admin server listening on %s", ln.Addr())
+	return s, nil
+}
+
+// Addr 实际监听地址
+func (s *Server) Addr() string {
+	if s == nil {
+		return ""
+	}
+	return s.ln.Addr().String()
+}
+
+// Close 关闭管理端服务
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
+
+// writeJSON 输出 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		logger.Warnf("Failed to write admin response: %v", err)
+	}
+}
+
+// writeError 输出错误响应
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
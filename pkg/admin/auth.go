@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// token 会改变进程状态的接口（回滚、固定等）使用的 token，由 Start 设置
+var token atomic.Pointer[string]
+
+// HandleAuth 注册需要 token 的管理端接口，请求需携带 Authorization: Bearer <token>
+// 未配置 token 时这些接口不可用
+func HandleAuth(pattern string, handler http.HandlerFunc) {
+	Handle(pattern, requireToken(handler))
+}
+
+// requireToken 校验请求中的 token
+func requireToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		want := token.Load()
+		if want == nil || *want == "" {
+			writeError(w, http.StatusForbidden, errors.New("admin token is not configured, set -admin-token or ADMIN_TOKEN to enable this endpoint"))
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(*want)) != 1 {
+			logger.Warnf("Rejected unauthorized admin request %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing admin token"))
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"helloworld/pkg/config"
+)
+
+func init() {
+	Handle("GET /config/version", handleConfigVersion)
+	Handle("GET /config/effective", handleConfigEffective)
+	Handle("GET /config/history", handleConfigHistory)
+	Handle("GET /config/history/{version}", handleConfigHistoryVersion)
+	HandleAuth("POST /config/rollback/{version}", handleConfigRollback)
+	HandleAuth("POST /config/pin/{version}", handleConfigPin)
+	HandleAuth("POST /config/unpin", handleConfigUnpin)
+}
+
+// handleConfigVersion 当前版本、Nacos 最新版本和固定版本
+func handleConfigVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, config.CurrentVersion())
+}
+
+// handleConfigEffective 当前生效的配置（合并 overrides、解析占位符之后）、命中的 overrides 和实例标签
+// 占位符可能引入环境变量中的密码，敏感配置值替换为 ******
+func handleConfigEffective(w http.ResponseWriter, r *http.Request) {
+	snap := config.Snapshot()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"version":   snap.Version(),
+		"overrides": snap.Overrides(),
+		"instance":  config.Instance(),
+		"config":    config.RedactConfig(snap.GetAll()),
+	})
+}
+
+// handleConfigHistory 历史版本列表，不包含配置内容
+func handleConfigHistory(w http.ResponseWriter, r *http.Request) {
+	versions := config.History()
+	for i := range versions {
+		versions[i].Content = ""
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+// versionView 单个历史版本的响应，不输出原始内容，Config 为解析后隐藏了敏感配置值的配置
+type versionView struct {
+	config.ConfigVersion
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+// handleConfigHistoryVersion 单个历史版本，敏感配置值替换为 ******；无法解析的版本只返回版本信息
+func handleConfigHistoryVersion(w http.ResponseWriter, r *http.Request) {
+	version, ok := parseVersion(w, r)
+	if !ok {
+		return
+	}
+	v, err := config.GetVersion(version)
+	if err != nil {
+		writeVersionError(w, err)
+		return
+	}
+	view := versionView{ConfigVersion: v}
+	if view.Config, err = config.RedactContent(v.Content); err != nil {
+		logger.Warnf("Failed to parse config v%d for display: %v", version, err)
+	}
+	view.Content = ""
+	writeJSON(w, http.StatusOK, view)
+}
+
+// handleConfigRollback 回滚到历史版本，Nacos 下一次推送时会被覆盖
+func handleConfigRollback(w http.ResponseWriter, r *http.Request) {
+	version, ok := parseVersion(w, r)
+	if !ok {
+		return
+	}
+	logger.Warnf("Config rollback to v%d requested by %s", version, r.RemoteAddr)
+	v, err := config.Rollback(version)
+	if err != nil {
+		writeVersionError(w, err)
+		return
+	}
+	v.Content = ""
+	writeJSON(w, http.StatusOK, v)
+}
+
+// handleConfigPin 固定到历史版本，忽略 Nacos 推送直到 unpin
+func handleConfigPin(w http.ResponseWriter, r *http.Request) {
+	version, ok := parseVersion(w, r)
+	if !ok {
+		return
+	}
+	logger.Warnf("Config pin to v%d requested by %s", version, r.RemoteAddr)
+	v, err := config.Pin(version)
+	if err != nil {
+		writeVersionError(w, err)
+		return
+	}
+	v.Content = ""
+	writeJSON(w, http.StatusOK, v)
+}
+
+// handleConfigUnpin 取消固定，恢复 Nacos 最新配置
+func handleConfigUnpin(w http.ResponseWriter, r *http.Request) {
+	logger.Warnf("Config unpin requested by %s", r.RemoteAddr)
+	v, err := config.Unpin()
+	if err != nil {
+		writeVersionError(w, err)
+		return
+	}
+	v.Content = ""
+	writeJSON(w, http.StatusOK, v)
+}
+
+// parseVersion 解析路径中的版本号，支持 "3" 和 "v3"
+func parseVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.PathValue("version")
+	version, err := strconv.ParseInt(strings.TrimPrefix(raw, "v"), 10, 64)
+	if err != nil || version <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid version %q", raw))
+		return 0, false
+	}
+	return version, true
+}
+
+// writeVersionError 版本不存在返回 404，其余返回 409
+func writeVersionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, config.ErrVersionNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeError(w, http.StatusConflict, err)
+}
@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"helloworld/pkg/buildinfo"
+	"helloworld/pkg/config"
+)
+
+// startTime 进程启动时间
+var startTime = time.Now()
+
+var (
+	statusMu       sync.RWMutex
+	statusSections = map[string]func() interface{}{
+		"build":   func() interface{} { return buildinfo.Get() },
+		"profile": func() interface{} { return config.ActiveProfile() },
+		"config":  func() interface{} { return config.CurrentVersion() },
+	}
+)
+
+func init() {
+	Handle("GET /status", handleStatus)
+}
+
+// AddStatus 在 GET /status 中增加一个分段，fn 在每次请求时调用，同名分段会被替换
+func AddStatus(section string, fn func() interface{}) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	statusSections[section] = fn
+}
+
+// handleStatus 进程状态：启动时间、构建信息、profile、配置版本以及各模块注册的分段
+func handleStatus(w http.ResponseWriter, r *http.Request) {
+	statusMu.RLock()
+	sections := make(map[string]func() interface{}, len(statusSections))
+	for name, fn := range statusSections {
+		sections[name] = fn
+	}
+	statusMu.RUnlock()
+
+	status := map[string]interface{}{
+		"start_time": startTime.Format(time.RFC3339),
+		"uptime":     time.Since(startTime).Round(time.Second).String(),
+	}
+	for name, fn := range sections {
+		status[name] = fn()
+	}
+	writeJSON(w, http.StatusOK, status)
+}
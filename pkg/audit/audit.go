@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"helloworld/pkg/config"
+	"helloworld/pkg/log"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// logger 审计模块自身的日志（写入失败、webhook 失败等），审计记录写入单独的文件
+var logger = log.Named("helloworld/pkg/audit")
+
+// 事件类型
+const (
+	EventConfigChange   = "config_change"   // 配置变更，见 config.ConfigChange
+	EventComponentState = "component_state" // 组件状态变化，见 ComponentState
+)
+
+// Event 审计事件，写入审计日志并发送到 webhook
+type Event struct {
+	Type      string               `json:"type"`
+	App       string               `json:"app"`
+	Instance  string               `json:"instance"`
+	Timestamp time.Time            `json:"timestamp"`
+	Config    *config.ConfigChange `json:"config,omitempty"`
+	Component *ComponentState      `json:"component,omitempty"`
+}
+
+// state 审计的运行时状态，整体替换以支持重新初始化
+type state struct {
+	app      string
+	instance string
+	webhook  *Webhook // 未配置 webhook 时为 nil
+
+	mu   sync.Mutex
+	file io.WriteCloser // 每行一个 JSON 事件，未配置文件时为 nil
+}
+
+// closeTimeout 重新初始化时等待旧 webhook 发送完成的时间
+const closeTimeout = 5 * time.Second
+
+var (
+	current      atomic.Pointer[state]
+	registerOnce sync.Once
+)
+
+// Setup 初始化审计日志和 webhook，并注册配置变更审计；需要在加载应用配置之前调用，以记录启动时的配置
+func Setup(appName string, opts config.AuditOptions) error {
+	s := &state{app: appName, instance: config.DetectInstance().ID}
+	if opts.File != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.File), 0755); err != nil {
+			return fmt.Errorf("failed to create audit log directory: %w", err)
+		}
+		s.file = &lumberjack.Logger{
+			Filename:   opts.File,
+			MaxSize:    100,
+			MaxBackups: 10,
+			MaxAge:     90,
+			Compress:   true,
+			LocalTime:  true,
+		}
+	}
+	if opts.WebhookURL != "" {
+		s.webhook = NewWebhook(WebhookOptions{URL: opts.WebhookURL, Retries: opts.WebhookRetries})
+	}
+	if old := current.Swap(s); old != nil {
+		old.shutdown(closeTimeout)
+	}
+
+	registerOnce.Do(func() { config.RegisterAuditor(RecordConfigChange) })
+	logger.Infof("Audit enabled: file=%q, webhook=%v", opts.File, opts.WebhookURL != "")
+	return nil
+}
+
+// Close 关闭审计日志，等待 webhook 发送完队列中的事件（最多 timeout）
+func Close(timeout time.Duration) {
+	if s := current.Swap(nil); s != nil {
+		s.shutdown(timeout)
+	}
+}
+
+// shutdown 关闭 webhook 和审计日志文件
+func (s *state) shutdown(timeout time.Duration) {
+	if s.webhook != nil {
+		s.webhook.Close(timeout)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		_ = s.file.Close()
+		s.file = nil
+	}
+}
+
+// write 追加一行审计记录
+func (s *state) write(e Event) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		logger.Errorf("Failed to encode audit event: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		logger.Errorf("Failed to write audit log: %v", err)
+	}
+}
+
+// RecordConfigChange 记录配置变更，由 config.RegisterAuditor 注册
+func RecordConfigChange(change config.ConfigChange) {
+	record(Event{Type: EventConfigChange, Timestamp: change.Timestamp, Config: &change})
+}
+
+// record 写入审计日志并放入 webhook 发送队列
+func record(e Event) {
+	s := current.Load()
+	if s == nil {
+		return
+	}
+	e.App = s.app
+	e.Instance = s.instance
+	s.write(e)
+	if s.webhook != nil {
+		s.webhook.Send(e)
+	}
+}
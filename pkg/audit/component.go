@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 组件状态
+const (
+	StateUp   = "up"
+	StateDown = "down"
+)
+
+// ComponentState 组件状态变化
+type ComponentState struct {
+	Name  string `json:"name"`            // 组件名称，如 redis、mysql
+	State string `json:"state"`           // up 或 down
+	Prev  string `json:"prev,omitempty"`  // 变化前的状态，首次检测时为空
+	Error string `json:"error,omitempty"` // down 的原因
+}
+
+var (
+	statesMu sync.Mutex
+	states   = make(map[string]string)
+)
+
+// ReportState 报告组件当前状态，只有状态变化时记录审计事件并发送 webhook
+// 首次报告 up 视为正常启动，不产生事件
+func ReportState(name string, err error) {
+	cur := StateUp
+	if err != nil {
+		cur = StateDown
+	}
+
+	statesMu.Lock()
+	prev, seen := states[name]
+	states[name] = cur
+	statesMu.Unlock()
+	if prev == cur || !seen && cur == StateUp {
+		return
+	}
+
+	change := &ComponentState{Name: name, State: cur, Prev: prev}
+	if err != nil {
+		change.Error = err.Error()
+		logger.Errorf("Component %s is down: %v", name, err)
+	} else {
+		logger.Infof("Component %s recovered", name)
+	}
+	record(Event{Type: EventComponentState, Timestamp: time.Now(), Component: change})
+}
+
+// Watch 每隔 interval 调用 check 检查组件状态，直到 ctx 结束；check 的超时为 interval
+func Watch(ctx context.Context, name string, interval time.Duration, check func(ctx context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			err := check(checkCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+			ReportState(name, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
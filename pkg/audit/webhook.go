@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// webhookTotal webhook 发送结果，通过管理端 /metrics 暴露
+var webhookTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "helloworld",
+	Subsystem: "audit",
+	Name:      "webhook_total",
+	Help:      "Audit webhook deliveries by result.",
+}, []string{"result"})
+
+// webhook 发送结果
+const (
+	webhookSent    = "sent"    // 发送成功（含重试后成功）
+	webhookFailed  = "failed"  // 重试后仍然失败
+	webhookDropped = "dropped" // 队列已满或已关闭，未发送
+)
+
+// WebhookOptions webhook 选项
+type WebhookOptions struct {
+	URL       string
+	Retries   int           // 失败后的重试次数
+	Backoff   time.Duration // 首次重试的等待时间，之后每次翻倍，默认 1s
+	Timeout   time.Duration // 单次请求超时，默认 5s
+	QueueSize int           // 待发送事件的队列长度，默认 100
+	Client    *http.Client  // 默认 http.DefaultClient
+}
+
+// Webhook 把事件以 JSON POST 到指定地址，后台单协程按顺序发送
+// 网络错误、5xx 和 429 会重试，其他 4xx 视为事件本身有问题，不重试
+type Webhook struct {
+	opts  WebhookOptions
+	queue chan interface{}
+	done  chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewWebhook 创建 webhook 并启动后台发送协程
+func NewWebhook(opts WebhookOptions) *Webhook {
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	w := &Webhook{
+		opts:  opts,
+		queue: make(chan interface{}, opts.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Send 放入发送队列，不阻塞；队列已满时丢弃并输出警告
+func (w *Webhook) Send(event interface{}) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		webhookTotal.WithLabelValues(webhookDropped).Inc()
+		return
+	}
+	select {
+	case w.queue <- event:
+	default:
+		webhookTotal.WithLabelValues(webhookDropped).Inc()
+		logger.Warnf("Audit webhook queue is full, dropping event")
+	}
+}
+
+// Close 停止接收事件，等待队列中的事件发送完成，超过 timeout 后直接返回
+func (w *Webhook) Close(timeout time.Duration) {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+	case <-time.After(timeout):
+		logger.Warnf("Audit webhook did not finish within %s, %d event(s) not sent", timeout, len(w.queue))
+	}
+}
+
+// run 后台发送协程
+func (w *Webhook) run() {
+	defer close(w.done)
+	for event := range w.queue {
+		if err := w.deliver(event); err != nil {
+			webhookTotal.WithLabelValues(webhookFailed).Inc()
+			logger.Errorf("Audit webhook failed after %d attempt(s): %v", w.opts.Retries+1, err)
+			continue
+		}
+		webhookTotal.WithLabelValues(webhookSent).Inc()
+	}
+}
+
+// deliver 发送单个事件，失败时按指数退避重试
+func (w *Webhook) deliver(event interface{}) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	backoff := w.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := w.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= w.opts.Retries {
+			return err
+		}
+		logger.Warnf("Audit webhook attempt %d failed, retrying in %s: %v", attempt+1, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post 发送一次请求，返回失败是否可以重试
+func (w *Webhook) post(body []byte) (retryable bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("webhook %s returned %s", w.opts.URL, resp.Status)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newTestWebhook 创建指向 handler 的 webhook，重试间隔缩短到 1ms
+func newTestWebhook(t *testing.T, handler http.HandlerFunc, opts WebhookOptions) *Webhook {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	opts.URL = srv.URL
+	opts.Backoff = time.Millisecond
+	return NewWebhook(opts)
+}
+
+// counter 当前的 webhook 结果计数
+func counter(result string) float64 {
+	return testutil.ToFloat64(webhookTotal.WithLabelValues(result))
+}
+
+func TestWebhookRetriesServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+	w := newTestWebhook(t, func(rw http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= 2 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}, WebhookOptions{Retries: 3})
+
+	sent := counter(webhookSent)
+	w.Send(map[string]string{"type": "test"})
+	w.Close(5 * time.Second)
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	if got := counter(webhookSent) - sent; got != 1 {
+		t.Errorf("sent = %v, want 1", got)
+	}
+}
+
+func TestWebhookGivesUpAfterRetries(t *testing.T) {
+	var attempts atomic.Int32
+	w := newTestWebhook(t, func(rw http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		rw.WriteHeader(http.StatusBadGateway)
+	}, WebhookOptions{Retries: 2})
+
+	failed := counter(webhookFailed)
+	w.Send(map[string]string{"type": "test"})
+	w.Close(5 * time.Second)
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	if got := counter(webhookFailed) - failed; got != 1 {
+		t.Errorf("failed = %v, want 1", got)
+	}
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	w := newTestWebhook(t, func(rw http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		rw.WriteHeader(http.StatusBadRequest)
+	}, WebhookOptions{Retries: 3})
+
+	failed := counter(webhookFailed)
+	w.Send(map[string]string{"type": "test"})
+	w.Close(5 * time.Second)
+
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+	if got := counter(webhookFailed) - failed; got != 1 {
+		t.Errorf("failed = %v, want 1", got)
+	}
+}
+
+func TestWebhookDropsWhenQueueIsFull(t *testing.T) {
+	received := make(chan struct{}, 3)
+	release := make(chan struct{})
+	w := newTestWebhook(t, func(rw http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	}, WebhookOptions{QueueSize: 1})
+
+	dropped := counter(webhookDropped)
+	sent := counter(webhookSent)
+
+	// 第一个事件被发送协程取走并阻塞在请求中，第二个占满队列，第三个被丢弃
+	w.Send(map[string]int{"seq": 1})
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook did not receive the first event")
+	}
+	w.Send(map[string]int{"seq": 2})
+	w.Send(map[string]int{"seq": 3})
+	if got := counter(webhookDropped) - dropped; got != 1 {
+		t.Errorf("dropped = %v, want 1", got)
+	}
+
+	close(release)
+	w.Close(5 * time.Second)
+	if got := counter(webhookSent) - sent; got != 2 {
+		t.Errorf("sent = %v, want 2", got)
+	}
+
+	// 关闭后发送的事件同样丢弃
+	w.Send(map[string]int{"seq": 4})
+	if got := counter(webhookDropped) - dropped; got != 2 {
+		t.Errorf("dropped after close = %v, want 2", got)
+	}
+}
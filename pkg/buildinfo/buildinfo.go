@@ -0,0 +1,103 @@
+// Package buildinfo 构建信息，版本、提交和构建时间在链接时注入：
+//
+//	go build -ldflags "-X helloworld/pkg/buildinfo.Version=1.2.0 \
+//	  -X helloworld/pkg/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X helloworld/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// 未注入时提交和构建时间取 go 工具链记录的 vcs 信息（go build 时有，go run 时没有）
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 链接时通过 -ldflags "-X" 注入
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// unknown 未注入且没有 vcs 信息时的取值
+const unknown = "unknown"
+
+// Info 构建信息
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// buildInfo 构建信息指标，值恒为 1，通过管理端 /metrics 暴露
+var buildInfo = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "helloworld",
+	Name:      "build_info",
+	Help:      "Build information, value is always 1.",
+}, []string{"version", "commit", "build_time", "go_version"})
+
+var (
+	once sync.Once
+	info Info
+)
+
+func init() {
+	i := Get()
+	buildInfo.WithLabelValues(i.Version, i.Commit, i.BuildTime, i.GoVersion).Set(1)
+}
+
+// Get 返回构建信息
+func Get() Info {
+	once.Do(func() {
+		info = Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			var revision, modified string
+			for _, s := range bi.Settings {
+				switch s.Key {
+				case "vcs.revision":
+					revision = s.Value
+				case "vcs.modified":
+					modified = s.Value
+				case "vcs.time":
+					if info.BuildTime == "" {
+						info.BuildTime = s.Value
+					}
+				}
+			}
+			// 有未提交修改时加 -dirty 后缀
+			if info.Commit == "" && revision != "" {
+				info.Commit = shortCommit(revision)
+				if modified == "true" {
+					info.Commit += "-dirty"
+				}
+			}
+		}
+		if info.Commit == "" {
+			info.Commit = unknown
+		}
+		if info.BuildTime == "" {
+			info.BuildTime = unknown
+		}
+	})
+	return info
+}
+
+// String 版本信息，用于 -version 和启动日志
+func String() string {
+	i := Get()
+	return fmt.Sprintf("version %s (commit %s, built %s, %s)", i.Version, i.Commit, i.BuildTime, i.GoVersion)
+}
+
+// shortCommit 取提交 hash 的前 12 位
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
@@ -0,0 +1,311 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"helloworld/pkg/log"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound loader 返回该错误表示数据不存在，会按 NegativeTTL 缓存空结果
+var ErrNotFound = errors.New("cache: not found")
+
+// 缓存值的标记字节：区分正常值和空结果
+const (
+	flagNegative byte = '0'
+	flagValue    byte = '1'
+)
+
+// defaultTimeout Redis 单次操作默认超时
+const defaultTimeout = 200 * time.Millisecond
+
+// defaultLoadTimeout 合并后的回源默认超时
+const defaultLoadTimeout = 5 * time.Second
+
+// Options 缓存配置
+type Options struct {
+	Prefix      string        // key 前缀
+	TTL         time.Duration // 默认过期时间
+	Jitter      float64       // 过期时间随机抖动比例（0~1），避免大量 key 同时失效
+	NegativeTTL time.Duration // 空结果缓存时间，0 表示不缓存空结果
+	Timeout     time.Duration // Redis 单次操作超时
+	LoadTimeout time.Duration // 回源超时，回源由多个调用方共享，不受单个调用方的取消和超时影响
+}
+
+// Cache 基于 Redis 的 cache-aside 缓存
+// Redis 为 nil 或不可用时直接回源，不影响业务
+type Cache struct {
+	client *redis.Client
+	opts   atomic.Pointer[Options] // UpdateTTL 整体替换
+	group  singleflight.Group
+}
+
+// New 创建缓存，client 可以为 nil
+func New(client *redis.Client, opts Options) *Cache {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.LoadTimeout <= 0 {
+		opts.LoadTimeout = defaultLoadTimeout
+	}
+	opts.Jitter = clampJitter(opts.Jitter)
+	c := &Cache{client: client}
+	c.opts.Store(&opts)
+	return c
+}
+
+// UpdateTTL 更新过期时间、空结果缓存时间和抖动比例，用于配置热更新，之后写入的缓存生效
+// Prefix 和超时在创建时确定，修改需要重建 Cache
+func (c *Cache) UpdateTTL(ttl, negativeTTL time.Duration, jitter float64) {
+	if c == nil {
+		return
+	}
+	opts := *c.opts.Load()
+	opts.TTL = ttl
+	opts.NegativeTTL = negativeTTL
+	opts.Jitter = clampJitter(jitter)
+	c.opts.Store(&opts)
+}
+
+// clampJitter 抖动比例限制在 [0, 1]
+func clampJitter(jitter float64) float64 {
+	if jitter < 0 {
+		return 0
+	}
+	if jitter > 1 {
+		return 1
+	}
+	return jitter
+}
+
+// callOptions 单次调用的选项
+type callOptions struct {
+	ttl         time.Duration
+	negativeTTL time.Duration
+	codec       Codec
+	namespace   string
+}
+
+// Option 单次调用选项
+type Option func(*callOptions)
+
+// WithTTL 覆盖默认过期时间
+func WithTTL(ttl time.Duration) Option {
+	return func(o *callOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithNegativeTTL 覆盖空结果缓存时间
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(o *callOptions) {
+		o.negativeTTL = ttl
+	}
+}
+
+// WithCodec 指定编解码器，默认 JSONCodec
+func WithCodec(codec Codec) Option {
+	return func(o *callOptions) {
+		o.codec = codec
+	}
+}
+
+// WithNamespace 把 key 放入命名空间，Invalidate 后该命名空间下的缓存全部失效
+// 每次调用多一次 Redis 读取命名空间版本号
+func WithNamespace(ns string) Option {
+	return func(o *callOptions) {
+		o.namespace = ns
+	}
+}
+
+// Loader 回源函数
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// GetOrLoad 先查缓存，未命中时调用 loader 回源并写入缓存
+// 同一个 key 的并发回源会被合并（singleflight），并发调用方拿到的是同一个结果，不要修改返回值
+// 合并后的回源使用 LoadTimeout，不随发起回源的调用方取消；各调用方在自己的 ctx 结束时提前返回
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, loader Loader[T], opts ...Option) (T, error) {
+	var zero T
+	if c == nil {
+		return loader(ctx)
+	}
+
+	copts := c.opts.Load()
+	o := callOptions{ttl: copts.TTL, negativeTTL: copts.NegativeTTL, codec: JSONCodec}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	fullKey := copts.Prefix + key
+	redisOK := c.client != nil
+
+	// 命名空间版本号写在 key 中，读取失败时按 Redis 不可用处理，避免读到失效前的缓存
+	if redisOK && o.namespace != "" {
+		gen, err := c.generation(ctx, o.namespace)
+		if err != nil {
+			log.FromContext(ctx).Warnf("cache: redis get generation of %s failed, falling back to loader: %v", o.namespace, err)
+			redisOK = false
+		}
+		fullKey = fmt.Sprintf("%s%s:v%d:%s", copts.Prefix, o.namespace, gen, key)
+	}
+
+	// 1. 查缓存
+	if redisOK {
+		data, err := c.get(ctx, fullKey)
+		switch {
+		case err == nil:
+			var v T
+			hit, err := decode(data, &v, o.codec)
+			if err == nil {
+				if !hit {
+					return zero, ErrNotFound
+				}
+				return v, nil
+			}
+			log.FromContext(ctx).Warnf("cache: failed to decode %s, reloading: %v", fullKey, err)
+		case errors.Is(err, redis.Nil):
+		default:
+			log.FromContext(ctx).Warnf("cache: redis get %s failed, falling back to loader: %v", fullKey, err)
+			redisOK = false
+		}
+	}
+
+	// 2. 回源（合并并发请求）
+	ch := c.group.DoChan(fullKey, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), copts.LoadTimeout)
+		defer cancel()
+		v, err := loader(ctx)
+		if !redisOK {
+			return v, err
+		}
+
+		switch {
+		case errors.Is(err, ErrNotFound):
+			if o.negativeTTL > 0 {
+				c.set(ctx, fullKey, []byte{flagNegative}, jitter(o.negativeTTL, copts.Jitter))
+			}
+		case err == nil:
+			data, encErr := encode(v, o.codec)
+			if encErr != nil {
+				log.FromContext(ctx).Warnf("cache: failed to encode %s: %v", fullKey, encErr)
+			} else if o.ttl > 0 {
+				c.set(ctx, fullKey, data, jitter(o.ttl, copts.Jitter))
+			}
+		}
+		return v, err
+	})
+	var res singleflight.Result
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+	if res.Err != nil {
+		return zero, res.Err
+	}
+	return res.Val.(T), nil
+}
+
+// Invalidate 使命名空间下的缓存全部失效：递增版本号，之后的读取使用新的 key，旧 key 按 TTL 过期
+// 需要在写库成功之后调用，此后开始的读取一定回源到新数据
+func (c *Cache) Invalidate(ctx context.Context, ns string) error {
+	if c == nil || c.client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opts.Load().Timeout)
+	defer cancel()
+	return c.client.Incr(ctx, c.generationKey(ns)).Err()
+}
+
+// generation 读取命名空间的版本号，不存在时为 0
+func (c *Cache) generation(ctx context.Context, ns string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Load().Timeout)
+	defer cancel()
+	gen, err := c.client.Get(ctx, c.generationKey(ns)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return gen, err
+}
+
+// generationKey 命名空间版本号的 key
+func (c *Cache) generationKey(ns string) string {
+	return c.opts.Load().Prefix + ns + ":gen"
+}
+
+// Delete 删除缓存
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if c == nil || c.client == nil || len(keys) == 0 {
+		return nil
+	}
+	fullKeys := make([]string, len(keys))
+	for i, k := range keys {
+		fullKeys[i] = c.opts.Load().Prefix + k
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Load().Timeout)
+	defer cancel()
+	return c.client.Del(ctx, fullKeys...).Err()
+}
+
+// get 带超时读取
+func (c *Cache) get(ctx context.Context, key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Load().Timeout)
+	defer cancel()
+	return c.client.Get(ctx, key).Bytes()
+}
+
+// set 带超时写入，失败只记录日志
+func (c *Cache) set(ctx context.Context, key string, data []byte, ttl time.Duration) {
+	// 调用方取消不应影响写缓存
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opts.Load().Timeout)
+	defer cancel()
+	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		log.FromContext(ctx).Warnf("cache: redis set %s failed: %v", key, err)
+	}
+}
+
+// jitter 在 ttl 基础上增加 [0, ttl*ratio) 的随机时长
+func jitter(ttl time.Duration, ratio float64) time.Duration {
+	if ratio <= 0 || ttl <= 0 {
+		return ttl
+	}
+	max := int64(float64(ttl) * ratio)
+	if max <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Int63n(max))
+}
+
+// encode 编码为 标记字节 + 数据
+func encode(v interface{}, codec Codec) ([]byte, error) {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, len(data)+1)
+	buf = append(buf, flagValue)
+	return append(buf, data...), nil
+}
+
+// decode 解码缓存值，hit 为 false 表示缓存的是空结果
+func decode(data []byte, v interface{}, codec Codec) (hit bool, err error) {
+	if len(data) == 0 {
+		return false, fmt.Errorf("empty cache value")
+	}
+	switch data[0] {
+	case flagNegative:
+		return false, nil
+	case flagValue:
+		return true, codec.Unmarshal(data[1:], v)
+	default:
+		return false, fmt.Errorf("unknown cache flag %q", data[0])
+	}
+}
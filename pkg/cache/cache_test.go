@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestCache 使用进程内 Redis 创建缓存，不加抖动便于检查过期时间
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return New(client, Options{Prefix: "test:", TTL: time.Minute, NegativeTTL: 5 * time.Second}), mr
+}
+
+type item struct {
+	Name string `json:"name"`
+}
+
+// countingLoader 记录回源次数
+func countingLoader(calls *atomic.Int32, v item, err error) Loader[item] {
+	return func(context.Context) (item, error) {
+		calls.Add(1)
+		return v, err
+	}
+}
+
+func TestGetOrLoadCachesValue(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	var calls atomic.Int32
+	load := countingLoader(&calls, item{Name: "a"}, nil)
+
+	for i := 0; i < 3; i++ {
+		got, err := GetOrLoad(ctx, c, "k", load)
+		if err != nil || got.Name != "a" {
+			t.Fatalf("GetOrLoad() = %v, %v", got, err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader calls = %d, want 1", n)
+	}
+	if ttl := mr.TTL("test:k"); ttl != time.Minute {
+		t.Errorf("ttl = %v, want 1m", ttl)
+	}
+
+	mr.FastForward(time.Minute)
+	if _, err := GetOrLoad(ctx, c, "k", load); err != nil {
+		t.Fatal(err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("loader calls after expiry = %d, want 2", n)
+	}
+}
+
+func TestGetOrLoadCollapsesConcurrentLoads(t *testing.T) {
+	c, _ := newTestCache(t)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (item, error) {
+		calls.Add(1)
+		<-release
+		return item{Name: "a"}, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := GetOrLoad(context.Background(), c, "k", load)
+			if err == nil && got.Name != "a" {
+				err = errors.New("unexpected value " + got.Name)
+			}
+			errs <- err
+		}()
+	}
+	// 等待所有调用方进入回源合并
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader calls = %d, want 1", n)
+	}
+}
+
+func TestGetOrLoadCallerCancel(t *testing.T) {
+	c, mr := newTestCache(t)
+	release := make(chan struct{})
+	load := func(ctx context.Context) (item, error) {
+		<-release
+		return item{Name: "a"}, ctx.Err()
+	}
+
+	// 调用方超时提前返回，回源继续执行并写入缓存
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := GetOrLoad(ctx, c, "k", load); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetOrLoad() error = %v, want deadline exceeded", err)
+	}
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for !mr.Exists("test:k") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !mr.Exists("test:k") {
+		t.Error("value was not cached after the caller gave up")
+	}
+}
+
+func TestGetOrLoadNegativeCaching(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	var calls atomic.Int32
+	load := countingLoader(&calls, item{}, ErrNotFound)
+
+	for i := 0; i < 3; i++ {
+		if _, err := GetOrLoad(ctx, c, "missing", load); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetOrLoad() error = %v, want ErrNotFound", err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader calls = %d, want 1", n)
+	}
+	if ttl := mr.TTL("test:missing"); ttl != 5*time.Second {
+		t.Errorf("negative ttl = %v, want 5s", ttl)
+	}
+
+	// 其他错误不缓存
+	var failing atomic.Int32
+	boom := countingLoader(&failing, item{}, errors.New("db down"))
+	GetOrLoad(ctx, c, "broken", boom)
+	GetOrLoad(ctx, c, "broken", boom)
+	if n := failing.Load(); n != 2 {
+		t.Errorf("failing loader calls = %d, want 2", n)
+	}
+
+	// NegativeTTL 为 0 时不缓存空结果
+	var uncached atomic.Int32
+	GetOrLoad(ctx, c, "missing2", countingLoader(&uncached, item{}, ErrNotFound), WithNegativeTTL(0))
+	GetOrLoad(ctx, c, "missing2", countingLoader(&uncached, item{}, ErrNotFound), WithNegativeTTL(0))
+	if n := uncached.Load(); n != 2 {
+		t.Errorf("loader calls without negative caching = %d, want 2", n)
+	}
+}
+
+func TestInvalidateNamespace(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	var calls atomic.Int32
+	load := countingLoader(&calls, item{Name: "a"}, nil)
+
+	GetOrLoad(ctx, c, "list", load, WithNamespace("greet"))
+	GetOrLoad(ctx, c, "stats", load, WithNamespace("greet"))
+	GetOrLoad(ctx, c, "other", load)
+	if err := c.Invalidate(ctx, "greet"); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	GetOrLoad(ctx, c, "list", load, WithNamespace("greet"))
+	GetOrLoad(ctx, c, "stats", load, WithNamespace("greet"))
+	GetOrLoad(ctx, c, "other", load)
+
+	// 命名空间内的 key 全部重新回源，命名空间外的不受影响
+	if n := calls.Load(); n != 5 {
+		t.Errorf("loader calls = %d, want 5", n)
+	}
+}
+
+func TestGetOrLoadWithoutRedis(t *testing.T) {
+	down := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { _ = down.Close() })
+
+	tests := []struct {
+		name  string
+		cache *Cache
+	}{
+		{name: "nil cache"},
+		{name: "nil client", cache: New(nil, Options{TTL: time.Minute})},
+		{name: "redis down", cache: New(down, Options{TTL: time.Minute})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			load := countingLoader(&calls, item{Name: "a"}, nil)
+			for i := 0; i < 2; i++ {
+				got, err := GetOrLoad(context.Background(), tt.cache, "k", load, WithNamespace("greet"))
+				if err != nil || got.Name != "a" {
+					t.Fatalf("GetOrLoad() = %v, %v", got, err)
+				}
+			}
+			if n := calls.Load(); n != 2 {
+				t.Errorf("loader calls = %d, want 2", n)
+			}
+			if err := tt.cache.Invalidate(context.Background(), "greet"); tt.name != "redis down" && err != nil {
+				t.Errorf("Invalidate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateTTL(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	var calls atomic.Int32
+
+	c.UpdateTTL(10*time.Second, time.Second, 2)
+	GetOrLoad(ctx, c, "k", countingLoader(&calls, item{Name: "a"}, nil))
+	GetOrLoad(ctx, c, "missing", countingLoader(&calls, item{}, ErrNotFound))
+
+	// 抖动比例限制在 [0, 1]
+	if ttl := mr.TTL("test:k"); ttl < 10*time.Second || ttl >= 20*time.Second {
+		t.Errorf("ttl = %v, want [10s, 20s)", ttl)
+	}
+	if ttl := mr.TTL("test:missing"); ttl < time.Second || ttl >= 2*time.Second {
+		t.Errorf("negative ttl = %v, want [1s, 2s)", ttl)
+	}
+}
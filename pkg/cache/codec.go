@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec 缓存值的编解码器
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal 解码到 v，v 必须是指针
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec JSON 编解码器，适用于普通结构体
+var JSONCodec Codec = jsonCodec{}
+
+// ProtoCodec protobuf 编解码器，适用于 proto.Message
+var ProtoCodec Codec = protoCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type protoCodec struct{}
+
+func (protoCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("cache: %T is not a proto.Message", v)
+	}
+	return proto.Marshal(m)
+}
+
+// Unmarshal 支持 *Msg 和 **Msg 两种形式，后者在 GetOrLoad[*Msg] 中使用，为 nil 时自动分配
+func (protoCodec) Unmarshal(data []byte, v interface{}) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cache: unmarshal target must be a non-nil pointer, got %T", v)
+	}
+	elem := rv.Elem()
+	if elem.Kind() == reflect.Ptr && elem.IsNil() {
+		elem.Set(reflect.New(elem.Type().Elem()))
+	}
+	m, ok := elem.Interface().(proto.Message)
+	if !ok {
+		return fmt.Errorf("cache: %T is not a proto.Message", elem.Interface())
+	}
+	return proto.Unmarshal(data, m)
+}
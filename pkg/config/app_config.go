@@ -1,175 +1,66 @@
 package config
 
-import (
-	"strings"
-	"sync"
+// defaultManager 包级函数使用的默认配置管理器，由 InitAppConfig 绑定到 Nacos 或 SetSource 设置的来源
+var defaultManager = NewManager(nil)
 
-	conf "dubbo.apache.org/dubbo-go/v3/common/config"
-	"dubbo.apache.org/dubbo-go/v3/config_center"
-	"github.com/dubbogo/gost/log/logger"
-	"gopkg.in/yaml.v3"
-)
-
-// AppConfig 应用配置（全局单例）
-var appConfig = &AppConfigManager{
-	data: make(map[string]interface{}),
-	mu:   sync.RWMutex{},
-}
-
-// AppConfigManager 应用配置管理器
-type AppConfigManager struct {
-	data map[string]interface{}
-	mu   sync.RWMutex
+// Default 返回默认配置管理器
+func Default() *Manager {
+	return defaultManager
 }
 
-// InitAppConfig Init 从 dubbo-go 配置中心初始化应用配置
+// InitAppConfig Init 从 dubbo-go 配置中心初始化应用配置，已通过 SetSource 设置配置来源时使用该来源
 func InitAppConfig(dataID, group string) error {
-	dynamicConfig := conf.GetEnvInstance().GetDynamicConfiguration()
-	if dynamicConfig == nil {
-		return nil // 配置中心未启动，返回nil
-	}
-
-	// 获取配置内容
-	content, err := dynamicConfig.GetProperties(dataID, config_center.WithGroup(group))
-	if err != nil {
-		logger.Errorf("Failed to get config from center: %v", err)
-		return err
-	}
-
-	// 解析配置
-	var configMap map[string]interface{}
-	if err := yaml.Unmarshal([]byte(content), &configMap); err != nil {
-		logger.Errorf("Failed to parse config: %v", err)
-		return err
+	if defaultManager.source == nil {
+		source, err := NewNacosSource(dataID, group)
+		if err != nil {
+			return nil // 配置中心未启动，返回nil
+		}
+		defaultManager.source = source
 	}
-
-	// 更新配置
-	appConfig.mu.Lock()
-	appConfig.data = configMap
-	appConfig.mu.Unlock()
-
-	logger.Infof("App config initialized: %+v", configMap)
-
-	// 添加配置监听器
-	dynamicConfig.AddListener(dataID, &appConfigListener{}, config_center.WithGroup(group))
-
-	return nil
+	return defaultManager.Start()
 }
 
-// appConfigListener 配置监听器
-type appConfigListener struct{}
-
-// Process 实现 ConfigurationListener 接口
-func (l *appConfigListener) Process(event *config_center.ConfigChangeEvent) {
-	logger.Infof("App config changed: %v", event.Value)
-
-	valueStr, ok := event.Value.(string)
-	if !ok {
-		logger.Errorf("Failed to convert config value to string")
-		return
-	}
-
-	var configMap map[string]interface{}
-	if err := yaml.Unmarshal([]byte(valueStr), &configMap); err != nil {
-		logger.Errorf("Failed to parse config: %v", err)
-		return
-	}
-
-	// 更新配置
-	appConfig.mu.Lock()
-	appConfig.data = configMap
-	appConfig.mu.Unlock()
-
-	logger.Infof("App config updated successfully")
+// SetSource 设置默认管理器的配置来源（如 NewFileSource），需要在 InitAppConfig 之前调用
+func SetSource(source Source) {
+	defaultManager.source = source
 }
 
-// Get 获取配置值（支持点号路径，如 "redis.host"）
-func Get(key string) interface{} {
-	appConfig.mu.RLock()
-	defer appConfig.mu.RUnlock()
-
-	// 支持点号路径，如 "redis.host"
-	keys := strings.Split(key, ".")
-	var current interface{} = appConfig.data
-
-	for _, k := range keys {
-		if m, ok := current.(map[string]interface{}); ok {
-			current = m[k]
-		} else {
-			return nil
-		}
-	}
+// SetHistoryOptions 设置默认管理器的配置历史选项，需要在 InitAppConfig 之前调用
+func SetHistoryOptions(opts HistoryOptions) {
+	WithHistory(opts)(defaultManager)
+}
 
-	return current
+// SetFormat 设置默认管理器的配置格式，需要在 InitAppConfig 之前调用，为空时按 data ID 扩展名判断
+func SetFormat(format string) {
+	WithFormat(format)(defaultManager)
 }
 
-// GetString 获取字符串配置
-func GetString(key string) string {
-	val := Get(key)
-	if val == nil {
-		return ""
-	}
-	if s, ok := val.(string); ok {
-		return s
-	}
-	return ""
+// SetReloadOptions 设置默认管理器的配置更新选项，需要在 InitAppConfig 之前调用
+func SetReloadOptions(opts ReloadOptions) {
+	WithReloadOptions(opts)(defaultManager)
 }
 
-// GetInt 获取整数配置
-func GetInt(key string) int {
-	val := Get(key)
-	if val == nil {
-		return 0
-	}
-	switch v := val.(type) {
-	case int:
-		return v
-	case int64:
-		return int(v)
-	case float64:
-		return int(v)
-	}
-	return 0
+// RegisterChangeListener 注册配置变化回调，配置更新成功后按注册顺序调用
+func RegisterChangeListener(l ChangeListener) {
+	defaultManager.RegisterChangeListener(l)
 }
 
-// GetBool 获取布尔配置
-func GetBool(key string) bool {
-	val := Get(key)
-	if val == nil {
-		return false
-	}
-	if b, ok := val.(bool); ok {
-		return b
-	}
-	return false
+// Get 获取配置值（支持点号路径，如 "redis.host"）
+func Get(key string) interface{} {
+	return defaultManager.Get(key)
 }
 
 // GetStringMap 获取map配置
 func GetStringMap(key string) map[string]interface{} {
-	val := Get(key)
-	if val == nil {
-		return nil
-	}
-	if m, ok := val.(map[string]interface{}); ok {
-		return m
-	}
-	return nil
+	return defaultManager.GetStringMap(key)
 }
 
 // IsSet 检查配置是否存在
 func IsSet(key string) bool {
-	return Get(key) != nil
+	return defaultManager.IsSet(key)
 }
 
 // GetAll 获取所有配置
 func GetAll() map[string]interface{} {
-	appConfig.mu.RLock()
-	defer appConfig.mu.RUnlock()
-
-	// 返回副本
-	result := make(map[string]interface{})
-	for k, v := range appConfig.data {
-		result[k] = v
-	}
-	return result
+	return defaultManager.GetAll()
 }
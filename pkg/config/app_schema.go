@@ -0,0 +1,181 @@
+package config
+
+// 构建 schema 的辅助函数
+func object(props map[string]*Schema) *Schema { return &Schema{Type: TypeObject, Properties: props} }
+func mapOf(items *Schema) *Schema             { return &Schema{Type: TypeMap, Items: items} }
+func listOf(items *Schema) *Schema            { return &Schema{Type: TypeList, Items: items} }
+func str() *Schema                            { return &Schema{Type: TypeString} }
+func enum(values ...string) *Schema           { return &Schema{Type: TypeString, Enum: values} }
+func boolean() *Schema                        { return &Schema{Type: TypeBool} }
+func duration() *Schema                       { return &Schema{Type: TypeDuration, Min: bound(0)} }
+func anyValue() *Schema                       { return &Schema{Type: TypeAny} }
+func integer(min, max *float64) *Schema       { return &Schema{Type: TypeInt, Min: min, Max: max} }
+func number(min, max *float64) *Schema        { return &Schema{Type: TypeFloat, Min: min, Max: max} }
+func nonNegInt() *Schema                      { return integer(bound(0), nil) }
+func positiveInt() *Schema                    { return integer(bound(1), nil) }
+func port() *Schema                           { return integer(bound(1), bound(65535)) }
+func ratio() *Schema                          { return number(bound(0), bound(1)) }
+func bound(v float64) *float64                { return &v }
+
+// logLevels 日志级别取值
+var logLevels = []string{"debug", "info", "warn", "error", "fatal", "off"}
+
+// AppSchema 应用配置（Nacos 中 Data ID 为应用名的 YAML）的完整 schema
+// 新增配置项时需要同步修改这里，否则 check-config 会报 unknown key
+func AppSchema() *Schema {
+	rateLimitRule := object(map[string]*Schema{
+		"rate":  number(bound(0), nil),
+		"burst": nonNegInt(),
+	})
+
+	return object(map[string]*Schema{
+		// dubbo 自身的配置由 dubbo-go 校验
+		"dubbo": anyValue(),
+
+		// 配置所属环境，与 -profile 不一致时拒绝
+		"profile": enum(ProfileNames()...),
+
+		// 按实例标签灰度的配置，config 合并后按本 schema 校验
+		"overrides": listOf(object(map[string]*Schema{
+			"id": str(),
+			"match": object(map[string]*Schema{
+				"hostnames": listOf(str()),
+				"cidrs":     listOf(str()),
+				"zones":     listOf(str()),
+				"labels":    mapOf(str()),
+				"percent":   number(bound(0), bound(100)),
+			}),
+			"config": anyValue(),
+		})),
+
+		"redis": object(map[string]*Schema{
+			"host":            str(),
+			"port":            port(),
+			"password":        str(),
+			"db":              integer(bound(0), bound(15)),
+			"pool_size":       positiveInt(),
+			"min_idle_conns":  nonNegInt(),
+			"conn_timeout":    duration(),
+			"read_timeout":    duration(),
+			"write_timeout":   duration(),
+			"pool_timeout":    duration(),
+			"idle_timeout":    duration(),
+			"idle_check_freq": duration(),
+			"max_conn_age":    duration(),
+			"log_level":       enum("error", "warn", "info", "debug", "off"),
+		}),
+
+		"mysql": object(map[string]*Schema{
+			"host":                    str(),
+			"port":                    port(),
+			"username":                str(),
+			"password":                str(),
+			"database":                str(),
+			"charset":                 str(),
+			"location":                str(),
+			"conn_timeout":            duration(),
+			"read_timeout":            duration(),
+			"write_timeout":           duration(),
+			"conn_max_lifetime":       duration(),
+			"max_idle_conns":          nonNegInt(),
+			"max_open_conns":          nonNegInt(),
+			"log_level":               enum("silent", "error", "warn", "info"),
+			"slow_threshold":          duration(),
+			"ignore_record_not_found": boolean(),
+			"redact_sql":              boolean(),
+		}),
+
+		"log": object(map[string]*Schema{
+			"level":       enum(logLevels...),
+			"levels":      mapOf(enum(logLevels...)),
+			"filename":    str(),
+			"max_size":    positiveInt(),
+			"max_age":     nonNegInt(),
+			"max_backups": nonNegInt(),
+			"compress":    boolean(),
+			"outputs": listOf(object(map[string]*Schema{
+				"type":        enum("stdout", "stderr", "file", "rotating_file", "syslog", "udp"),
+				"encoder":     enum("json", "console", "logfmt"),
+				"level":       enum(logLevels...),
+				"filename":    str(),
+				"network":     enum("", "udp", "tcp", "unix"),
+				"address":     str(),
+				"tag":         str(),
+				"max_size":    positiveInt(),
+				"max_age":     nonNegInt(),
+				"max_backups": nonNegInt(),
+				"compress":    boolean(),
+				"daily":       boolean(),
+				"local_time":  boolean(),
+			})),
+			"sampling": object(map[string]*Schema{
+				"enabled":    boolean(),
+				"tick":       duration(),
+				"initial":    nonNegInt(),
+				"thereafter": nonNegInt(),
+				"levels": mapOf(object(map[string]*Schema{
+					"initial":    nonNegInt(),
+					"thereafter": nonNegInt(),
+				})),
+			}),
+			"error_rate_limit": object(map[string]*Schema{
+				"enabled":  boolean(),
+				"interval": duration(),
+				"burst":    positiveInt(),
+			}),
+			"access": object(map[string]*Schema{
+				"enabled":     boolean(),
+				"filename":    str(),
+				"max_size":    positiveInt(),
+				"max_age":     nonNegInt(),
+				"max_backups": nonNegInt(),
+				"sampling":    mapOf(ratio()),
+			}),
+		}),
+
+		"tracing": object(map[string]*Schema{
+			"exporter":      enum("otlp", "stdout", "memory", "none"),
+			"endpoint":      str(),
+			"protocol":      enum("grpc", "http"),
+			"insecure":      boolean(),
+			"sampler_ratio": ratio(),
+		}),
+
+		"cache": object(map[string]*Schema{
+			"enabled":      boolean(),
+			"prefix":       str(),
+			"ttl":          duration(),
+			"negative_ttl": duration(),
+			"jitter":       ratio(),
+			"ttls":         mapOf(duration()),
+		}),
+
+		// 功能开关，规则的取值类型由 type 决定，由 flags 包检查
+		"flags": mapOf(object(map[string]*Schema{
+			"type":     enum(FlagTypeBool, FlagTypeString),
+			"variants": listOf(str()),
+			"default":  anyValue(),
+			"rules": listOf(object(map[string]*Schema{
+				"callers":    listOf(str()),
+				"attributes": mapOf(str()),
+				"percent":    number(bound(0), bound(100)),
+				"value":      anyValue(),
+			})),
+		})),
+
+		"ratelimit": object(map[string]*Schema{
+			"enabled": boolean(),
+			"prefix":  str(),
+			"methods": mapOf(rateLimitRule),
+			"callers": mapOf(rateLimitRule),
+		}),
+	})
+}
+
+// ValidateAppConfig 使用 AppSchema 校验应用配置，没有问题时返回 nil
+func ValidateAppConfig(data map[string]interface{}) error {
+	if errs := AppSchema().Validate(data); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 配置变更结果
+const (
+	AuditApplied    = "applied"    // 生效，包括回滚、固定、取消固定和启动时使用的历史版本
+	AuditRejected   = "rejected"   // 解析或校验失败，保留当前配置
+	AuditPinned     = "pinned"     // 固定版本期间收到，只记录不生效
+	AuditSuperseded = "superseded" // 灰度等待期间被替代或取消
+)
+
+// redacted 审计信息中替换敏感配置值的占位符
+const redacted = "******"
+
+// secretKeyParts 配置路径包含这些词时，审计信息中不输出值
+var secretKeyParts = []string{"password", "passwd", "secret", "token", "credential", "private_key", "access_key", "api_key", "dsn"}
+
+// AuditOptions 配置变更审计选项
+type AuditOptions struct {
+	File           string // 审计日志文件，为空时不写文件
+	WebhookURL     string // 审计和组件状态变化的 webhook 地址，为空时不发送
+	WebhookRetries int    // webhook 失败后的重试次数
+}
+
+// KeyChange 单个配置项的变化，敏感配置的值为 ******
+type KeyChange struct {
+	Key string      `json:"key"`
+	Op  string      `json:"op"` // added、removed、changed
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// ConfigChange 一次配置变更的审计信息
+type ConfigChange struct {
+	Timestamp time.Time   `json:"timestamp"`
+	DataID    string      `json:"data_id"`
+	Group     string      `json:"group,omitempty"`
+	Version   int64       `json:"version"`
+	Source    string      `json:"source"` // nacos、file、rollback:v3 等
+	Hash      string      `json:"hash"`
+	Outcome   string      `json:"outcome"` // applied、rejected、pinned、superseded
+	Error     string      `json:"error,omitempty"`
+	Diff      []KeyChange `json:"diff,omitempty"` // 相对当前生效配置的变化，解析失败时为空
+}
+
+// ChangeAuditor 配置变更审计回调，在配置更新的流程中同步调用，不应阻塞
+type ChangeAuditor func(change ConfigChange)
+
+// locatedSource 能提供 data ID 和分组的 Source，用于审计信息
+type locatedSource interface {
+	Location() (dataID, group string)
+}
+
+// RegisterAuditor 注册配置变更审计回调
+func (m *Manager) RegisterAuditor(a ChangeAuditor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auditors = append(m.auditors, a)
+}
+
+// RegisterAuditor 向默认管理器注册配置变更审计回调
+func RegisterAuditor(a ChangeAuditor) {
+	defaultManager.RegisterAuditor(a)
+}
+
+// audit 记录一次配置变更，next 为新配置（解析失败时为 nil），与当前生效的配置比较生成 diff
+// 比较的是解析占位符之前的配置：${DB_PASS} 这类引用环境变量的值在 diff 中保持原样，不会因为 key 不像敏感配置而泄露
+func (m *Manager) audit(v ConfigVersion, outcome string, next *configTree, err error) {
+	m.mu.Lock()
+	auditors := make([]ChangeAuditor, len(m.auditors))
+	copy(auditors, m.auditors)
+	m.mu.Unlock()
+	if len(auditors) == 0 {
+		return
+	}
+
+	change := ConfigChange{
+		Timestamp: time.Now(),
+		Version:   v.Version,
+		Source:    v.Source,
+		Hash:      v.Hash,
+		Outcome:   outcome,
+	}
+	if ls, ok := m.source.(locatedSource); ok {
+		change.DataID, change.Group = ls.Location()
+	}
+	if err != nil {
+		change.Error = err.Error()
+	}
+	if next != nil {
+		change.Diff = diffConfig(m.tree.Load().raw, next.raw)
+	}
+	for _, a := range auditors {
+		a(change)
+	}
+}
+
+// diffConfig 按配置路径比较两份配置，对象逐层展开，列表整体比较，结果按路径排序
+func diffConfig(old, next map[string]interface{}) []KeyChange {
+	var changes []KeyChange
+	diffValue("", old, next, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// diffValue 递归比较，old 或 next 为 nil 表示不存在
+func diffValue(key string, old, next interface{}, changes *[]KeyChange) {
+	om, oldIsMap := old.(map[string]interface{})
+	nm, nextIsMap := next.(map[string]interface{})
+	// 对象整体新增或删除时逐个字段记录，其中的敏感字段同样会被隐藏
+	if key == "" || (oldIsMap || old == nil) && (nextIsMap || next == nil) && (oldIsMap || nextIsMap) {
+		for k, ov := range om {
+			diffValue(joinPath(key, k), ov, nm[k], changes)
+		}
+		for k, nv := range nm {
+			if _, ok := om[k]; !ok {
+				diffValue(joinPath(key, k), nil, nv, changes)
+			}
+		}
+		return
+	}
+
+	var change KeyChange
+	switch {
+	case old == nil && next == nil:
+		return
+	case old == nil:
+		change = KeyChange{Key: key, Op: "added", New: next}
+	case next == nil:
+		change = KeyChange{Key: key, Op: "removed", Old: old}
+	case reflect.DeepEqual(old, next):
+		return
+	default:
+		change = KeyChange{Key: key, Op: "changed", Old: old, New: next}
+	}
+	change.Old = redactValue(key, change.Old)
+	change.New = redactValue(key, change.New)
+	*changes = append(*changes, change)
+}
+
+// redactValue 隐藏敏感配置的值，对象和列表中的敏感字段同样隐藏；URL 形式的值去掉其中的用户名和密码
+func redactValue(key string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if isSecretKey(key) {
+		return redacted
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			result[k] = redactValue(joinPath(key, k), item)
+		}
+		return result
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, item := range val {
+			list[i] = redactValue(key, item)
+		}
+		return list
+	case string:
+		return stripUserinfo(val)
+	}
+	return v
+}
+
+// stripUserinfo 去掉 URL 中的用户名和密码，如 redis://:pass@host:6379；不是 URL 的值原样返回
+func stripUserinfo(s string) string {
+	if !strings.Contains(s, "@") || !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.User == nil {
+		return s
+	}
+	u.User = nil
+	return u.String()
+}
+
+// RedactConfig 返回配置的副本，敏感配置值替换为 ******，规则与审计 diff 相同
+func RedactConfig(data map[string]interface{}) map[string]interface{} {
+	result, _ := redactValue("", data).(map[string]interface{})
+	return result
+}
+
+// RedactContent 按配置格式解析配置内容并隐藏敏感配置值，用于管理端展示历史版本
+func (m *Manager) RedactContent(content string) (map[string]interface{}, error) {
+	data, err := decodeConfig(m.format, []byte(content))
+	if err != nil {
+		return nil, err
+	}
+	return RedactConfig(data), nil
+}
+
+// RedactContent 使用默认管理器的配置格式解析并隐藏敏感配置值
+func RedactContent(content string) (map[string]interface{}, error) {
+	return defaultManager.RedactContent(content)
+}
+
+// isSecretKey 判断配置路径是否为敏感配置，如 redis.password、sms.api_key
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
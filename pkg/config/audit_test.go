@@ -0,0 +1,39 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAuditDiffDoesNotExpandEnv(t *testing.T) {
+	t.Setenv("AUDIT_TEST_AUTH", "s3cret-old")
+	t.Setenv("AUDIT_TEST_AUTH_NEXT", "s3cret-new")
+	m, src := newTestManager(t, "db:\n  auth: ${AUDIT_TEST_AUTH}\n  host: a\n  password: p1\n")
+	var changes []ConfigChange
+	m.RegisterAuditor(func(c ConfigChange) { changes = append(changes, c) })
+
+	src.Set("db:\n  auth: ${AUDIT_TEST_AUTH_NEXT}\n  host: redis://u:p2@b:6379\n  password: p2\n")
+
+	if got := m.GetString("db.auth"); got != "s3cret-new" {
+		t.Fatalf("db.auth = %q, want the expanded value", got)
+	}
+	if len(changes) != 1 || changes[0].Outcome != AuditApplied {
+		t.Fatalf("changes = %+v, want one applied change", changes)
+	}
+	want := []KeyChange{
+		{Key: "db.auth", Op: "changed", Old: "${AUDIT_TEST_AUTH}", New: "${AUDIT_TEST_AUTH_NEXT}"},
+		{Key: "db.host", Op: "changed", Old: "a", New: "redis://b:6379"},
+		{Key: "db.password", Op: "changed", Old: redacted, New: redacted},
+	}
+	if !reflect.DeepEqual(changes[0].Diff, want) {
+		t.Errorf("diff = %+v, want %+v", changes[0].Diff, want)
+	}
+	b, _ := json.Marshal(changes[0])
+	for _, secret := range []string{"s3cret", "p1", "p2"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("audit record contains %q: %s", secret, b)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Bind 将配置项绑定到结构体，字段名取 yaml tag，转换规则与 GetXxx 相同
+// out 中已有的值作为默认值，配置中不存在的字段保持不变
+// 无法转换的字段不修改，返回包含所有字段错误的 ValidationErrors
+func (m *Manager) Bind(key string, out interface{}) error {
+	return bindKey(m, key, out)
+}
+
+// Bind 将默认管理器的配置项绑定到结构体
+func Bind(key string, out interface{}) error {
+	return defaultManager.Bind(key, out)
+}
+
+// bindKey 读取配置项并绑定到 out，绑定副本：interface{}、map 字段不能与生效的配置共享
+func bindKey(r valueReader, key string, out interface{}) error {
+	val := lookup(r.currentTree().data, key)
+	if val == nil {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	return bindValue(key, deepCopyValue(val), out)
+}
+
+// bindValue 将配置值绑定到 out，path 用于错误信息
+func bindValue(path string, val interface{}, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("bind target must be a non-nil pointer, got %T", out)
+	}
+	var errs ValidationErrors
+	bindReflect(path, val, rv.Elem(), &errs)
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+		return errs
+	}
+	return nil
+}
+
+// bindReflect 递归绑定
+func bindReflect(path string, val interface{}, rv reflect.Value, errs *ValidationErrors) {
+	if val == nil {
+		return
+	}
+	fail := func(err error) {
+		*errs = append(*errs, ValidationError{Path: path, Message: err.Error()})
+	}
+
+	switch {
+	case rv.Type() == durationType:
+		d, err := toDuration(val)
+		if err != nil {
+			fail(err)
+			return
+		}
+		rv.SetInt(int64(d))
+		return
+	case rv.Type() == timeType:
+		t, err := toTime(val)
+		if err != nil {
+			fail(err)
+			return
+		}
+		rv.Set(reflect.ValueOf(t))
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		bindReflect(path, val, rv.Elem(), errs)
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(val))
+	case reflect.String:
+		s, err := toString(val)
+		if err != nil {
+			fail(err)
+			return
+		}
+		rv.SetString(s)
+	case reflect.Bool:
+		b, err := toBool(val)
+		if err != nil {
+			fail(err)
+			return
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toInt64(val)
+		if err == nil && rv.OverflowInt(n) {
+			err = fmt.Errorf("integer %d overflows %s", n, rv.Type())
+		}
+		if err != nil {
+			fail(err)
+			return
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := toInt64(val)
+		if err == nil && (n < 0 || rv.OverflowUint(uint64(n))) {
+			err = fmt.Errorf("integer %d out of range for %s", n, rv.Type())
+		}
+		if err != nil {
+			fail(err)
+			return
+		}
+		rv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(val)
+		if err != nil {
+			fail(err)
+			return
+		}
+		rv.SetFloat(f)
+	case reflect.Slice:
+		list, err := toList(val)
+		if err != nil {
+			fail(err)
+			return
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(list), len(list))
+		before := len(*errs)
+		for i, item := range list {
+			bindReflect(fmt.Sprintf("%s[%d]", path, i), item, slice.Index(i), errs)
+		}
+		if len(*errs) == before {
+			rv.Set(slice)
+		}
+	case reflect.Map:
+		m, ok := val.(map[string]interface{})
+		if !ok || rv.Type().Key().Kind() != reflect.String {
+			fail(fmt.Errorf("cannot convert %s to %s", typeName(val), rv.Type()))
+			return
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		elemType := rv.Type().Elem()
+		for k, v := range m {
+			elem := reflect.New(elemType).Elem()
+			if existing := rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())); existing.IsValid() {
+				elem.Set(existing)
+			}
+			before := len(*errs)
+			bindReflect(joinPath(path, k), v, elem, errs)
+			if len(*errs) == before {
+				rv.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), elem)
+			}
+		}
+	case reflect.Struct:
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			fail(fmt.Errorf("cannot convert %s to object", typeName(val)))
+			return
+		}
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := fieldName(field)
+			if name == "-" {
+				continue
+			}
+			if v, ok := m[name]; ok {
+				bindReflect(joinPath(path, name), v, rv.Field(i), errs)
+			}
+		}
+	default:
+		fail(fmt.Errorf("unsupported bind target type %s", rv.Type()))
+	}
+}
+
+// fieldName 结构体字段对应的配置名：yaml tag，没有 tag 时为小写字段名
+func fieldName(f reflect.StructField) string {
+	if tag, ok := f.Tag.Lookup("yaml"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return strings.ToLower(f.Name)
+}
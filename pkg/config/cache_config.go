@@ -0,0 +1,51 @@
+package config
+
+import (
+	"time"
+)
+
+// CacheConfig 缓存配置
+type CacheConfig struct {
+	Enabled     bool                     `json:"enabled" yaml:"enabled"`
+	Prefix      string                   `json:"prefix" yaml:"prefix"`
+	TTL         time.Duration            `json:"ttl" yaml:"ttl"`
+	NegativeTTL time.Duration            `json:"negative_ttl" yaml:"negative_ttl"`
+	Jitter      float64                  `json:"jitter" yaml:"jitter"`
+	TTLs        map[string]time.Duration `json:"ttls" yaml:"ttls"` // 按缓存名称覆盖 TTL
+}
+
+// TTLFor 获取指定缓存名称的 TTL，未单独配置时返回默认 TTL
+func (cc *CacheConfig) TTLFor(name string) time.Duration {
+	if ttl, ok := cc.TTLs[name]; ok {
+		return ttl
+	}
+	return cc.TTL
+}
+
+// GetCacheConfig 获取缓存配置，未配置时使用默认值
+func (m *Manager) GetCacheConfig() *CacheConfig {
+	config := &CacheConfig{
+		Enabled:     true,
+		Prefix:      "helloworld:",
+		TTL:         30 * time.Second,
+		NegativeTTL: 5 * time.Second,
+		Jitter:      0.1,
+		TTLs:        make(map[string]time.Duration),
+	}
+
+	cacheMap := m.GetStringMap("cache")
+	if cacheMap == nil {
+		return config
+	}
+
+	if err := bindValue("cache", cacheMap, config); err != nil {
+		logger.Warnf("Invalid cache config, keeping defaults for invalid fields: %v", err)
+	}
+
+	return config
+}
+
+// GetCacheConfigFromDubbo 从 dubbo-go 配置中心获取缓存配置
+func GetCacheConfigFromDubbo() *CacheConfig {
+	return defaultManager.GetCacheConfig()
+}
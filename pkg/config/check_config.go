@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// CheckConfigCommand 子命令名称，用法：server check-config -file app.yaml
+// 配置格式由 -format 指定，未指定时按文件名或 data ID 的扩展名判断
+const CheckConfigCommand = "check-config"
+
+// RunCheckConfig 执行 check-config 子命令：校验本地文件或 Nacos 上的配置，返回进程退出码
+// 0 表示配置正确，1 表示存在校验错误，2 表示参数错误或读取配置失败
+func RunCheckConfig(args []string, stdout io.Writer) int {
+	fs := flag.NewFlagSet(CheckConfigCommand, flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	var (
+		file      = fs.String("file", "", "Local config file to check (takes precedence over Nacos)")
+		profile   = fs.String("profile", getEnv("APP_PROFILE"), "Environment profile, sets Nacos defaults and checks the profile key")
+		profiles  = fs.String("profiles-file", getEnv("APP_PROFILES_FILE"), "Profiles file overriding the built-in profile table")
+		nacosAddr = fs.String("nacos-addr", getEnv("NACOS_ADDR"), "Nacos server address (default: profile address)")
+		namespace = fs.String("namespace", getEnv("NACOS_NAMESPACE"), "Nacos namespace (default: profile namespace)")
+		group     = fs.String("group", getEnv("NACOS_GROUP"), "Nacos group (default: profile group)")
+		dataID    = fs.String("data-id", getEnv("NACOS_DATA_ID"), "Nacos data ID (default: profile data ID for $APP_NAME)")
+		timeout   = fs.Duration("timeout", 3*time.Second, "Nacos timeout")
+		format    = fs.String("format", getEnv("CONFIG_FORMAT"), "Config format: yaml, json, properties, toml (default: file or data ID extension)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := LoadProfiles(*profiles); err != nil {
+		fmt.Fprintln(stdout, err)
+		return 2
+	}
+	p, err := LookupProfile(*profile)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 2
+	}
+	*nacosAddr = getStringValue(*nacosAddr, "", p.RegistryAddr)
+	*namespace = getStringValue(*namespace, "", p.Namespace)
+	*group = getStringValue(*group, "", p.Group)
+	if appName := getEnv("APP_NAME"); *dataID == "" && appName != "" {
+		*dataID = p.DataID(appName)
+	}
+	if err := checkProfileTarget(p, *namespace, *nacosAddr); err != nil {
+		fmt.Fprintln(stdout, err)
+		return 2
+	}
+
+	var (
+		content []byte
+		source  string
+	)
+	if *file != "" {
+		source = *file
+		content, err = os.ReadFile(*file)
+		if *format == "" {
+			*format = FormatFromDataID(*file)
+		}
+	} else {
+		if *dataID == "" {
+			fmt.Fprintln(stdout, "either -file or -data-id is required")
+			return 2
+		}
+		source = fmt.Sprintf("nacos %s (namespace=%s, group=%s, data-id=%s)", *nacosAddr, *namespace, *group, *dataID)
+		content, err = fetchNacosConfig(*nacosAddr, *namespace, *group, *dataID, *timeout)
+		if *format == "" {
+			*format = FormatFromDataID(*dataID)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stdout, "failed to read %s: %v\n", source, err)
+		return 2
+	}
+
+	if _, err := lookupDecoder(*format); err != nil {
+		fmt.Fprintln(stdout, err)
+		return 2
+	}
+	data, err := decodeConfig(*format, content)
+	if err != nil {
+		fmt.Fprintf(stdout, "%s: %v\n", source, err)
+		return 1
+	}
+
+	// 基础配置（含 overrides 段的结构）校验一次，每个 override 合并到基础配置后再分别校验
+	warnings := quotedScalars(*format, data)
+	errs := checkConfigData(p, deepCopyMap(data))
+	if raw, ok := data[overridesKey]; ok {
+		list, err := parseOverrides(raw)
+		errs = append(errs, toValidationErrors(overridesKey, err)...)
+		delete(data, overridesKey)
+		reported := make(map[ValidationError]bool, len(errs))
+		for _, e := range errs {
+			reported[e] = true
+		}
+		for _, o := range list {
+			merged := deepCopyMap(data)
+			mergeMaps(merged, deepCopyMap(o.Config))
+			for _, e := range checkConfigData(p, merged) {
+				if reported[e] {
+					continue // 基础配置中已报告
+				}
+				e.Path = fmt.Sprintf("%s[%s] %s", overridesKey, o.ID, e.Path)
+				errs = append(errs, e)
+			}
+		}
+	}
+	code := 0
+	if len(errs) == 0 {
+		fmt.Fprintf(stdout, "%s: OK\n", source)
+	} else {
+		fmt.Fprintf(stdout, "%s: %d problem(s)\n", source, len(errs))
+		for _, e := range errs {
+			fmt.Fprintf(stdout, "  %s\n", e.Error())
+		}
+		code = 1
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintf(stdout, "%s: %d warning(s)\n", source, len(warnings))
+		for _, e := range warnings {
+			fmt.Fprintf(stdout, "  %s\n", e.Error())
+		}
+	}
+	return code
+}
+
+// quotedScalars 查找基础配置和各 override 中写成字符串的数字和布尔值，不影响退出码
+// properties 格式的值都是字符串，不检查
+func quotedScalars(format string, data map[string]interface{}) ValidationErrors {
+	format = strings.ToLower(format)
+	if format == FormatProperties || formatAliases[format] == FormatProperties {
+		return nil
+	}
+	schema := AppSchema()
+	found := schema.quotedScalars(data)
+	// override 格式错误时已作为问题报告
+	overrides, _ := parseOverrides(data[overridesKey])
+	for _, o := range overrides {
+		for _, e := range schema.quotedScalars(o.Config) {
+			e.Path = fmt.Sprintf("%s[%s] config.%s", overridesKey, o.ID, e.Path)
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// checkConfigData 解析占位符后按 schema 和 profile 校验
+func checkConfigData(p Profile, data map[string]interface{}) ValidationErrors {
+	data, err := interpolate(data)
+	if err != nil {
+		return toValidationErrors("", err)
+	}
+	errs := AppSchema().Validate(data)
+	if err := validateProfileOf(p, data); err != nil {
+		errs = append(errs, ValidationError{Path: "profile", Message: err.Error()})
+	}
+	return append(errs, checkFlags(data)...)
+}
+
+// toValidationErrors 把错误转换为校验错误列表，不是校验错误时使用 path 作为路径，nil 返回空列表
+func toValidationErrors(path string, err error) ValidationErrors {
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		return errs
+	}
+	var e ValidationError
+	if errors.As(err, &e) {
+		return ValidationErrors{e}
+	}
+	return ValidationErrors{{Path: path, Message: err.Error()}}
+}
+
+// fetchNacosConfig 直接从 Nacos 读取配置，不启动 dubbo 实例
+func fetchNacosConfig(addr, namespace, group, dataID string, timeout time.Duration) ([]byte, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nacos address %q: %w", addr, err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nacos port %q: %w", portStr, err)
+	}
+	// public 命名空间在 Nacos 中的 ID 为空
+	if namespace == "public" {
+		namespace = ""
+	}
+
+	client, err := clients.NewConfigClient(vo.NacosClientParam{
+		ClientConfig: constant.NewClientConfig(
+			constant.WithNamespaceId(namespace),
+			constant.WithTimeoutMs(uint64(timeout.Milliseconds())),
+			constant.WithNotLoadCacheAtStart(true),
+			constant.WithDisableUseSnapShot(true),
+			constant.WithLogDir(os.TempDir()),
+			constant.WithCacheDir(os.TempDir()),
+			constant.WithLogLevel("error"),
+		),
+		ServerConfigs: []constant.ServerConfig{*constant.NewServerConfig(host, port)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseClient()
+
+	content, err := client.GetConfig(vo.ConfigParam{DataId: dataID, Group: group})
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, fmt.Errorf("config not found")
+	}
+	return []byte(content), nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckConfigWarnsQuotedScalars(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string // 输出中应包含的行，为空表示没有警告
+	}{
+		{
+			name: "yaml",
+			file: "app.yaml",
+			content: `
+mysql:
+  port: "3306"
+  host: "3306"
+redis:
+  port: ${REDIS_PORT:6379}
+  db: "${REDIS_DB:0}"
+  pool_size: 10
+overrides:
+  - id: pool-canary
+    match: {percent: "10"}
+    config:
+      redis: {pool_size: "50"}
+`,
+			want: []string{
+				"app.yaml: OK",
+				"app.yaml: 3 warning(s)",
+				`  mysql.port: integer written as string "3306"`,
+				`  overrides[0].match.percent: number written as string "10"`,
+				`  overrides[pool-canary] config.redis.pool_size: integer written as string "50"`,
+			},
+		},
+		{
+			name:    "properties",
+			file:    "app.properties",
+			content: "mysql.port=3306\nredis.pool_size=10\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if code := RunCheckConfig([]string{"-file", path}, &out); code != 0 {
+				t.Fatalf("RunCheckConfig() = %d, output:\n%s", code, out.String())
+			}
+			got := strings.ReplaceAll(out.String(), path, tt.file)
+			if len(tt.want) == 0 {
+				if strings.Contains(got, "warning") {
+					t.Errorf("unexpected warnings:\n%s", got)
+				}
+				return
+			}
+			if want := strings.Join(tt.want, "\n") + "\n"; got != want {
+				t.Errorf("output:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 类型转换规则，所有 Get* 方法和 Bind 共用：
+//   - 整数：整数、没有小数部分的浮点数、数字字符串（"3306"）
+//   - 浮点数：整数、浮点数、数字字符串
+//   - 布尔：true/false，字符串 "true"/"false"/"1"/"0"/"yes"/"no"/"on"/"off"（不区分大小写），数字 1/0
+//   - 字符串：字符串，数字和布尔按字面值转换
+//   - 时长：Go duration 字符串（"3s"、"1m30s"），数字或数字字符串按秒处理
+//   - 时间：RFC3339、"2006-01-02 15:04:05"、"2006-01-02" 字符串，数字按 Unix 秒处理
+//   - 列表：YAML 列表，或逗号分隔的字符串（"a,b,c"）
+
+// timeLayouts 支持的时间格式
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+// toString 转换为字符串
+func toString(v interface{}) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case bool:
+		return strconv.FormatBool(s), nil
+	case int:
+		return strconv.Itoa(s), nil
+	case int64:
+		return strconv.FormatInt(s, 10), nil
+	case uint64:
+		return strconv.FormatUint(s, 10), nil
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), nil
+	case time.Time:
+		return s.Format(time.RFC3339Nano), nil
+	}
+	return "", fmt.Errorf("cannot convert %s to string", typeName(v))
+}
+
+// toInt64 转换为整数
+func toInt64(v interface{}) (int64, error) {
+	if s, ok := v.(string); ok {
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot convert %s to integer", typeName(v))
+		}
+		return n, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case uint64:
+		if n > math.MaxInt64 {
+			return 0, fmt.Errorf("integer %d overflows int64", n)
+		}
+		return int64(n), nil
+	}
+	// float64(math.MaxInt64) 为 2^63，本身已超出 int64 范围
+	f, ok := toNumber(v)
+	if !ok || f != math.Trunc(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("cannot convert %s to integer", typeName(v))
+	}
+	return int64(f), nil
+}
+
+// toInt 转换为 int
+func toInt(v interface{}) (int, error) {
+	n, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt || n < math.MinInt {
+		return 0, fmt.Errorf("integer %d overflows int", n)
+	}
+	return int(n), nil
+}
+
+// toFloat64 转换为浮点数
+func toFloat64(v interface{}) (float64, error) {
+	if s, ok := v.(string); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot convert %s to number", typeName(v))
+		}
+		return f, nil
+	}
+	f, ok := toNumber(v)
+	if !ok {
+		return 0, fmt.Errorf("cannot convert %s to number", typeName(v))
+	}
+	return f, nil
+}
+
+// toBool 转换为布尔
+func toBool(v interface{}) (bool, error) {
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(b)) {
+		case "true", "1", "yes", "on":
+			return true, nil
+		case "false", "0", "no", "off":
+			return false, nil
+		}
+	default:
+		// YAML 解码为 int，TOML 为 int64，JSON 为 float64
+		if f, ok := toNumber(v); ok && (f == 0 || f == 1) {
+			return f == 1, nil
+		}
+	}
+	return false, fmt.Errorf("cannot convert %s to boolean", typeName(v))
+}
+
+// toDuration 转换为时长，数字按秒处理
+func toDuration(v interface{}) (time.Duration, error) {
+	if s, ok := v.(string); ok {
+		s = strings.Trim(strings.TrimSpace(s), `"`)
+		if s == "" {
+			return 0, nil
+		}
+		if d, err := time.ParseDuration(s); err == nil {
+			return d, nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return time.Duration(f * float64(time.Second)), nil
+		}
+		return 0, fmt.Errorf("cannot convert %s to duration", typeName(v))
+	}
+	if d, ok := v.(time.Duration); ok {
+		return d, nil
+	}
+	f, ok := toNumber(v)
+	if !ok {
+		return 0, fmt.Errorf("cannot convert %s to duration", typeName(v))
+	}
+	return time.Duration(f * float64(time.Second)), nil
+}
+
+// toTime 转换为时间
+func toTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		s := strings.TrimSpace(t)
+		for _, layout := range timeLayouts {
+			if tm, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+				return tm, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot convert %s to time", typeName(v))
+	}
+	n, err := toInt64(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot convert %s to time", typeName(v))
+	}
+	return time.Unix(n, 0), nil
+}
+
+// toList 转换为列表，字符串按逗号分隔
+func toList(v interface{}) ([]interface{}, error) {
+	switch l := v.(type) {
+	case []interface{}:
+		return l, nil
+	case string:
+		if strings.TrimSpace(l) == "" {
+			return nil, nil
+		}
+		parts := strings.Split(l, ",")
+		list := make([]interface{}, len(parts))
+		for i, p := range parts {
+			list[i] = strings.TrimSpace(p)
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to list", typeName(v))
+}
+
+// toStringSlice 转换为字符串列表
+func toStringSlice(v interface{}) ([]string, error) {
+	list, err := toList(v)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(list))
+	for i, item := range list {
+		if out[i], err = toString(item); err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return out, nil
+}
+
+// toIntSlice 转换为整数列表
+func toIntSlice(v interface{}) ([]int, error) {
+	list, err := toList(v)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int, len(list))
+	for i, item := range list {
+		if out[i], err = toInt(item); err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return out, nil
+}
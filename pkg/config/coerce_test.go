@@ -0,0 +1,305 @@
+package config
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToInt64(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    int64
+		wantErr bool
+	}{
+		{in: 3306, want: 3306},
+		{in: int64(math.MaxInt64), want: math.MaxInt64},
+		{in: int64(math.MinInt64), want: math.MinInt64},
+		{in: uint64(42), want: 42},
+		{in: uint64(math.MaxInt64) + 1, wantErr: true},
+		{in: float64(8), want: 8},
+		{in: -2.0, want: -2},
+		{in: 1.5, wantErr: true},
+		{in: math.Pow(2, 63), wantErr: true},
+		{in: -math.Pow(2, 63), want: math.MinInt64},
+		{in: math.Inf(1), wantErr: true},
+		{in: " 3306 ", want: 3306},
+		{in: "3.5", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: true, wantErr: true},
+		{in: []interface{}{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := toInt64(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toInt64(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("toInt64(%#v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    float64
+		wantErr bool
+	}{
+		{in: 2, want: 2},
+		{in: int64(3), want: 3},
+		{in: 0.25, want: 0.25},
+		{in: float32(0.5), want: 0.5},
+		{in: " 1e3 ", want: 1000},
+		{in: "x", wantErr: true},
+		{in: false, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := toFloat64(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toFloat64(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("toFloat64(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToBool(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    bool
+		wantErr bool
+	}{
+		{in: true, want: true},
+		{in: "TRUE", want: true},
+		{in: " yes ", want: true},
+		{in: "on", want: true},
+		{in: "1", want: true},
+		{in: "off", want: false},
+		{in: "No", want: false},
+		{in: "0", want: false},
+		{in: 1, want: true},
+		{in: int64(1), want: true},
+		{in: int64(0), want: false},
+		{in: float64(1), want: true},
+		{in: float64(0), want: false},
+		{in: uint64(1), want: true},
+		{in: 2, wantErr: true},
+		{in: 0.5, wantErr: true},
+		{in: "maybe", wantErr: true},
+		{in: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := toBool(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toBool(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("toBool(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToString(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      interface{}
+		want    string
+		wantErr bool
+	}{
+		{in: "plain", want: "plain"},
+		{in: true, want: "true"},
+		{in: 42, want: "42"},
+		{in: int64(-7), want: "-7"},
+		{in: uint64(9), want: "9"},
+		{in: 1.5, want: "1.5"},
+		{in: float64(3), want: "3"},
+		{in: ts, want: "2024-05-01T08:00:00Z"},
+		{in: map[string]interface{}{}, wantErr: true},
+		{in: []interface{}{"a"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := toString(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toString(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("toString(%#v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToDuration(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "3s", want: 3 * time.Second},
+		{in: "1m30s", want: 90 * time.Second},
+		{in: `"500ms"`, want: 500 * time.Millisecond},
+		{in: "", want: 0},
+		{in: "5", want: 5 * time.Second},
+		{in: "0.5", want: 500 * time.Millisecond},
+		{in: 10, want: 10 * time.Second},
+		{in: int64(2), want: 2 * time.Second},
+		{in: 1.5, want: 1500 * time.Millisecond},
+		{in: time.Minute, want: time.Minute},
+		{in: "soon", wantErr: true},
+		{in: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := toDuration(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toDuration(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("toDuration(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToTime(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "2024-05-01T08:00:00Z", want: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)},
+		{in: "2024-05-01T08:00:00.5+08:00", want: time.Date(2024, 5, 1, 0, 0, 0, 5e8, time.UTC)},
+		{in: "2024-05-01 08:00:00", want: time.Date(2024, 5, 1, 8, 0, 0, 0, time.Local)},
+		{in: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)},
+		{in: 1714550400, want: time.Unix(1714550400, 0)},
+		{in: int64(0), want: time.Unix(0, 0)},
+		{in: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), want: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{in: "01/05/2024", wantErr: true},
+		{in: 1.5, wantErr: true},
+		{in: false, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := toTime(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toTime(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("toTime(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToSlices(t *testing.T) {
+	strTests := []struct {
+		in      interface{}
+		want    []string
+		wantErr bool
+	}{
+		{in: "a, b ,c", want: []string{"a", "b", "c"}},
+		{in: "single", want: []string{"single"}},
+		{in: "  ", want: []string{}},
+		{in: []interface{}{"a", 1, true}, want: []string{"a", "1", "true"}},
+		{in: []interface{}{map[string]interface{}{}}, wantErr: true},
+		{in: 5, wantErr: true},
+	}
+	for _, tt := range strTests {
+		got, err := toStringSlice(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toStringSlice(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("toStringSlice(%#v) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+
+	intTests := []struct {
+		in      interface{}
+		want    []int
+		wantErr bool
+	}{
+		{in: "1, 2,3", want: []int{1, 2, 3}},
+		{in: []interface{}{1, int64(2), float64(3), "4"}, want: []int{1, 2, 3, 4}},
+		{in: "1,x", wantErr: true},
+		{in: []interface{}{1.5}, wantErr: true},
+	}
+	for _, tt := range intTests {
+		got, err := toIntSlice(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toIntSlice(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("toIntSlice(%#v) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGetterErrorPaths(t *testing.T) {
+	snap := &ConfigSnapshot{tree: &configTree{data: map[string]interface{}{
+		"server": map[string]interface{}{
+			"port":    "20000",
+			"timeout": "3s",
+			"enabled": int64(1),
+			"name":    "demo",
+		},
+	}}}
+
+	if got, err := snap.GetIntE("server.port"); err != nil || got != 20000 {
+		t.Errorf("GetIntE(server.port) = %d, %v, want 20000", got, err)
+	}
+	if got, err := snap.GetBoolE("server.enabled"); err != nil || !got {
+		t.Errorf("GetBoolE(server.enabled) = %v, %v, want true", got, err)
+	}
+
+	_, err := snap.GetIntE("server.missing")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetIntE(server.missing) error = %v, want ErrKeyNotFound", err)
+	}
+	_, err = snap.GetIntE("server.name")
+	if err == nil || errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetIntE(server.name) error = %v, want conversion error", err)
+	} else if want := "config key server.name: "; !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("GetIntE(server.name) error = %q, want prefix %q", err, want)
+	}
+
+	if got := snap.GetIntOr("server.missing", 7); got != 7 {
+		t.Errorf("GetIntOr(server.missing) = %d, want 7", got)
+	}
+	if got := snap.GetIntOr("server.name", 7); got != 7 {
+		t.Errorf("GetIntOr(server.name) = %d, want 7", got)
+	}
+	if got := snap.GetDurationOr("server.timeout", time.Second); got != 3*time.Second {
+		t.Errorf("GetDurationOr(server.timeout) = %v, want 3s", got)
+	}
+	if got := snap.GetInt("server.name"); got != 0 {
+		t.Errorf("GetInt(server.name) = %d, want 0", got)
+	}
+}
+
+func TestInvalidValueWarnsOncePerVersion(t *testing.T) {
+	m, src := newTestManager(t, "server:\n  port: abc\n")
+
+	for i := 0; i < 3; i++ {
+		if got := m.GetIntOr("server.port", 8080); got != 8080 {
+			t.Fatalf("GetIntOr() = %d, want default 8080", got)
+		}
+	}
+	if m.currentTree().warnOnce("server.port") {
+		t.Error("server.port was not marked as warned")
+	}
+
+	// 新版本重新警告
+	src.Set("server:\n  port: xyz\n")
+	if !m.currentTree().warnOnce("server.port") {
+		t.Error("server.port is marked as warned in a new version")
+	}
+}
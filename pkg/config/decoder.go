@@ -0,0 +1,241 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/magiconair/properties"
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// 配置格式
+const (
+	FormatYAML       = "yaml"
+	FormatJSON       = "json"
+	FormatProperties = "properties"
+	FormatTOML       = "toml"
+)
+
+// Decoder 将配置内容解析为嵌套 map，结果经过 normalize 后与 YAML 的结构一致：
+// 对象为 map[string]interface{}，列表为 []interface{}，整数为 int，小数为 float64
+type Decoder func(content []byte) (map[string]interface{}, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		FormatYAML:       decodeYAML,
+		FormatJSON:       decodeJSON,
+		FormatProperties: decodeProperties,
+		FormatTOML:       decodeTOML,
+	}
+	// formatAliases 文件扩展名到格式的映射
+	formatAliases = map[string]string{
+		"yml":   FormatYAML,
+		"props": FormatProperties,
+	}
+)
+
+// RegisterDecoder 注册或替换配置格式的解析器，format 不区分大小写
+func RegisterDecoder(format string, d Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[strings.ToLower(format)] = d
+}
+
+// Formats 返回已注册的配置格式
+func Formats() []string {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	formats := make([]string, 0, len(decoders))
+	for f := range decoders {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// lookupDecoder 查找格式对应的解析器
+func lookupDecoder(format string) (Decoder, error) {
+	format = strings.ToLower(format)
+	if alias, ok := formatAliases[format]; ok {
+		format = alias
+	}
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	d, ok := decoders[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	return d, nil
+}
+
+// FormatFromDataID 根据 data ID 或文件名的扩展名判断配置格式，没有扩展名或扩展名未注册时为 yaml
+func FormatFromDataID(dataID string) string {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(dataID), "."))
+	if ext == "" {
+		return FormatYAML
+	}
+	if alias, ok := formatAliases[ext]; ok {
+		return alias
+	}
+	if _, err := lookupDecoder(ext); err != nil {
+		return FormatYAML
+	}
+	return ext
+}
+
+// decodeConfig 按格式解析配置内容
+func decodeConfig(format string, content []byte) (map[string]interface{}, error) {
+	d, err := lookupDecoder(format)
+	if err != nil {
+		return nil, err
+	}
+	data, err := d(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", format, err)
+	}
+	if data == nil {
+		return make(map[string]interface{}), nil
+	}
+	return normalizeMap(data), nil
+}
+
+// decodeYAML 解析 YAML
+func decodeYAML(content []byte) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if err := yaml.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decodeJSON 解析 JSON，数字保留原文以区分整数和小数
+func decodeJSON(content []byte) (map[string]interface{}, error) {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var data map[string]interface{}
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decodeProperties 解析 properties，点号分隔的 key 展开为嵌套 map，值均为字符串
+// ${...} 不在这里展开
+func decodeProperties(content []byte) (map[string]interface{}, error) {
+	loader := properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+	p, err := loader.LoadBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	keys := p.Keys()
+	sort.Strings(keys)
+	data := make(map[string]interface{})
+	for _, key := range keys {
+		val, _ := p.Get(key)
+		if err := setNested(data, key, val); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// decodeTOML 解析 TOML
+func decodeTOML(content []byte) (map[string]interface{}, error) {
+	tree, err := toml.LoadBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	return tree.ToMap(), nil
+}
+
+// setNested 按点号路径写入嵌套 map，路径上已有非 map 的值时返回错误（如同时存在 a=1 和 a.b=2）
+func setNested(data map[string]interface{}, key string, val interface{}) error {
+	parts := strings.Split(key, ".")
+	current := data
+	for i, k := range parts[:len(parts)-1] {
+		next, exists := current[k]
+		if !exists {
+			m := make(map[string]interface{})
+			current[k] = m
+			current = m
+			continue
+		}
+		m, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("key %s conflicts with %s", key, strings.Join(parts[:i+1], "."))
+		}
+		current = m
+	}
+	last := parts[len(parts)-1]
+	if _, exists := current[last]; exists {
+		return fmt.Errorf("key %s conflicts with nested keys under it", key)
+	}
+	current[last] = val
+	return nil
+}
+
+// normalizeMap 统一各格式解析出的值类型，见 normalizeValue
+func normalizeMap(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		m[k] = normalizeValue(v)
+	}
+	return m
+}
+
+// normalizeValue 统一值类型：JSON 数字、TOML 的 int64/uint64 转为 int 或 float64，
+// map[interface{}]interface{} 转为 map[string]interface{}，TOML 本地日期时间转为字符串
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return normalizeMap(val)
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[fmt.Sprint(k)] = normalizeValue(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalizeValue(item)
+		}
+		return val
+	case []map[string]interface{}:
+		list := make([]interface{}, len(val))
+		for i, item := range val {
+			list[i] = normalizeMap(item)
+		}
+		return list
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return normalizeValue(n)
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case int64:
+		if val >= math.MinInt && val <= math.MaxInt {
+			return int(val)
+		}
+		return val
+	case uint64:
+		if val <= math.MaxInt {
+			return int(val)
+		}
+		return val
+	case toml.LocalDate, toml.LocalDateTime, toml.LocalTime:
+		return fmt.Sprint(val)
+	}
+	return v
+}
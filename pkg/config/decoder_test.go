@@ -0,0 +1,238 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 同一份配置的不同格式，解析后与 YAML 的结构一致
+const (
+	sampleYAML = `
+app:
+  name: demo
+  ratio: 0.5
+  debug: true
+server:
+  port: 20000
+  tags: [a, b]
+  endpoints:
+    - name: primary
+      weight: 10
+    - name: backup
+      weight: 1
+`
+	sampleJSON = `{
+  "app": {"name": "demo", "ratio": 0.5, "debug": true},
+  "server": {
+    "port": 20000,
+    "tags": ["a", "b"],
+    "endpoints": [{"name": "primary", "weight": 10}, {"name": "backup", "weight": 1}]
+  }
+}`
+	sampleTOML = `
+[app]
+name = "demo"
+ratio = 0.5
+debug = true
+
+[server]
+port = 20000
+tags = ["a", "b"]
+
+[[server.endpoints]]
+name = "primary"
+weight = 10
+
+[[server.endpoints]]
+name = "backup"
+weight = 1
+`
+)
+
+func TestDecodersNormalizeToSameMap(t *testing.T) {
+	want, err := decodeConfig(FormatYAML, []byte(sampleYAML))
+	if err != nil {
+		t.Fatalf("decode yaml: %v", err)
+	}
+	for format, content := range map[string]string{FormatJSON: sampleJSON, FormatTOML: sampleTOML} {
+		got, err := decodeConfig(format, []byte(content))
+		if err != nil {
+			t.Errorf("decode %s: %v", format, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %#v\nwant %#v", format, got, want)
+		}
+	}
+}
+
+func TestDecodeProperties(t *testing.T) {
+	content := `
+app.name=demo
+app.debug=true
+server.port=20000
+server.tags=a,b
+# 注释
+mysql.dsn=${MYSQL_DSN}
+`
+	got, err := decodeConfig(FormatProperties, []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// properties 的值都是字符串，由 Get* 的类型转换处理
+	want := map[string]interface{}{
+		"app":    map[string]interface{}{"name": "demo", "debug": "true"},
+		"server": map[string]interface{}{"port": "20000", "tags": "a,b"},
+		"mysql":  map[string]interface{}{"dsn": "${MYSQL_DSN}"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("properties = %#v\nwant %#v", got, want)
+	}
+
+	// 与 YAML 解析结果读取到相同的值
+	yamlData, err := decodeConfig(FormatYAML, []byte(sampleYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := &ConfigSnapshot{tree: &configTree{data: got}}
+	yml := &ConfigSnapshot{tree: &configTree{data: yamlData}}
+	if props.GetInt("server.port") != yml.GetInt("server.port") ||
+		props.GetBool("app.debug") != yml.GetBool("app.debug") ||
+		!reflect.DeepEqual(props.GetStringSlice("server.tags"), yml.GetStringSlice("server.tags")) {
+		t.Errorf("properties and yaml read differently")
+	}
+}
+
+func TestDecodePropertiesConflict(t *testing.T) {
+	_, err := decodeConfig(FormatProperties, []byte("a=1\na.b=2\n"))
+	if err == nil || !strings.Contains(err.Error(), "key a.b conflicts with a") {
+		t.Errorf("error = %v, want conflict between a and a.b", err)
+	}
+}
+
+func TestSetNested(t *testing.T) {
+	data := make(map[string]interface{})
+	for _, kv := range [][2]string{{"a.b.c", "1"}, {"a.b.d", "2"}, {"a.e", "3"}, {"f", "4"}} {
+		if err := setNested(data, kv[0], kv[1]); err != nil {
+			t.Fatalf("setNested(%s) error = %v", kv[0], err)
+		}
+	}
+	want := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": "1", "d": "2"},
+			"e": "3",
+		},
+		"f": "4",
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %#v, want %#v", data, want)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "f.g", want: "key f.g conflicts with f"},
+		{key: "a.e.x", want: "key a.e.x conflicts with a.e"},
+		{key: "a.b", want: "key a.b conflicts with nested keys under it"},
+		{key: "f", want: "key f conflicts with nested keys under it"},
+	}
+	for _, tt := range tests {
+		err := setNested(data, tt.key, "x")
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("setNested(%s) error = %v, want %q", tt.key, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeTOMLNormalizes(t *testing.T) {
+	content := `
+big = 9223372036854775807
+small = -3
+at = 2024-05-01T08:00:00
+
+[[rules]]
+id = "a"
+percent = 10
+
+[[rules]]
+id = "b"
+match = { zone = "z1" }
+`
+	got, err := decodeConfig(FormatTOML, []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := got["small"].(int); !ok || v != -3 {
+		t.Errorf("small = %#v, want int -3", got["small"])
+	}
+	if v, ok := got["big"].(int); !ok || v != 9223372036854775807 {
+		t.Errorf("big = %#v, want int", got["big"])
+	}
+	if got["at"] != "2024-05-01T08:00:00" {
+		t.Errorf("at = %#v, want local datetime string", got["at"])
+	}
+	want := []interface{}{
+		map[string]interface{}{"id": "a", "percent": 10},
+		map[string]interface{}{"id": "b", "match": map[string]interface{}{"zone": "z1"}},
+	}
+	if !reflect.DeepEqual(got["rules"], want) {
+		t.Errorf("rules = %#v, want %#v", got["rules"], want)
+	}
+}
+
+func TestDecodeJSONNumbers(t *testing.T) {
+	got, err := decodeConfig(FormatJSON, []byte(`{"i": 3, "f": 1.5, "e": 1e3, "list": [1, 2.5]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"i": 3, "f": 1.5, "e": float64(1000), "list": []interface{}{1, 2.5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json = %#v, want %#v", got, want)
+	}
+}
+
+func TestDecodeEmptyAndInvalid(t *testing.T) {
+	for _, format := range []string{FormatYAML, FormatJSON, FormatProperties, FormatTOML} {
+		got, err := decodeConfig(format, []byte(""))
+		if err != nil || got == nil || len(got) != 0 {
+			t.Errorf("decode empty %s = %#v, %v, want empty map", format, got, err)
+		}
+	}
+	for format, content := range map[string]string{
+		FormatYAML: "a: [1",
+		FormatJSON: `{"a":`,
+		FormatTOML: "a = ",
+	} {
+		if _, err := decodeConfig(format, []byte(content)); err == nil || !strings.HasPrefix(err.Error(), "invalid "+format) {
+			t.Errorf("decode invalid %s error = %v, want invalid %s", format, err, format)
+		}
+	}
+	if _, err := decodeConfig("xml", []byte("<a/>")); err == nil {
+		t.Error("decode xml error = nil, want unsupported format")
+	}
+}
+
+func TestFormatFromDataID(t *testing.T) {
+	tests := []struct {
+		dataID string
+		want   string
+	}{
+		{dataID: "go-server", want: FormatYAML},
+		{dataID: "go-server.yaml", want: FormatYAML},
+		{dataID: "go-server.yml", want: FormatYAML},
+		{dataID: "go-server.JSON", want: FormatJSON},
+		{dataID: "go-server.properties", want: FormatProperties},
+		{dataID: "go-server.props", want: FormatProperties},
+		{dataID: "go-server.toml", want: FormatTOML},
+		{dataID: "go-server.dev.toml", want: FormatTOML},
+		{dataID: "go-server.xml", want: FormatYAML},
+		{dataID: "/etc/app/config.json", want: FormatJSON},
+	}
+	for _, tt := range tests {
+		if got := FormatFromDataID(tt.dataID); got != tt.want {
+			t.Errorf("FormatFromDataID(%q) = %q, want %q", tt.dataID, got, tt.want)
+		}
+	}
+}
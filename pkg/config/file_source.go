@@ -0,0 +1,169 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// SourceFile 本地文件配置来源名称
+const SourceFile = "file"
+
+// defaultFileDebounce 文件变化事件的合并窗口
+const defaultFileDebounce = 500 * time.Millisecond
+
+// FileSource 本地文件配置来源，文件变化后重新加载，适用于挂载 Kubernetes ConfigMap 的部署
+//
+// 监听的是文件所在目录而不是文件本身：ConfigMap 更新时 kubelet 把新内容写入临时目录，
+// 再原子替换 ..data 符号链接，配置文件本身（指向 ..data/xxx 的链接）不会产生事件。
+// 编辑器先写临时文件再 rename 的保存方式同理。短时间内的多个事件合并为一次读取，内容未变化时不触发更新。
+type FileSource struct {
+	path     string
+	debounce time.Duration
+
+	mu      sync.Mutex
+	watcher *fsnotify.Watcher
+	last    []byte // 最近一次读取的内容
+
+	reloadMu sync.Mutex // 串行执行重新加载，上一次校验未结束时新的事件等待
+}
+
+// NewFileSource 创建本地文件配置来源，debounce 为 0 时使用 500ms
+func NewFileSource(path string, debounce time.Duration) *FileSource {
+	if debounce <= 0 {
+		debounce = defaultFileDebounce
+	}
+	return &FileSource{path: filepath.Clean(path), debounce: debounce}
+}
+
+// Name 实现 Source 接口
+func (s *FileSource) Name() string {
+	return SourceFile
+}
+
+// Location 审计信息中的 data ID 为文件路径
+func (s *FileSource) Location() (dataID, group string) {
+	return s.path, ""
+}
+
+// Format 按文件扩展名判断配置格式
+func (s *FileSource) Format() string {
+	return FormatFromDataID(s.path)
+}
+
+// Load 实现 Source 接口
+func (s *FileSource) Load() (string, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.last = content
+	s.mu.Unlock()
+	return string(content), nil
+}
+
+// Watch 实现 Source 接口，监听文件所在目录
+func (s *FileSource) Watch(onChange func(content string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(s.path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("watch %s: %w", filepath.Dir(s.path), err)
+	}
+
+	s.mu.Lock()
+	s.watcher = watcher
+	s.mu.Unlock()
+
+	go s.run(watcher, onChange)
+	logger.Infof("Watching app config file %s", s.path)
+	return nil
+}
+
+// Close 停止监听
+func (s *FileSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watcher == nil {
+		return nil
+	}
+	err := s.watcher.Close()
+	s.watcher = nil
+	return err
+}
+
+// run 处理目录事件，合并 debounce 窗口内的事件后重新读取文件
+func (s *FileSource) run(watcher *fsnotify.Watcher, onChange func(string)) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !s.relevant(event) {
+				continue
+			}
+			logger.Debugf("App config file event: %s", event)
+			if timer == nil {
+				timer = time.AfterFunc(s.debounce, func() { s.reload(onChange) })
+			} else {
+				timer.Reset(s.debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Errorf("App config file watcher error: %v", err)
+		}
+	}
+}
+
+// relevant 判断事件是否可能改变配置文件内容：文件本身、Kubernetes 的 ..data 链接及临时目录
+func (s *FileSource) relevant(event fsnotify.Event) bool {
+	if filepath.Clean(event.Name) == s.path {
+		return true
+	}
+	return strings.HasPrefix(filepath.Base(event.Name), "..")
+}
+
+// reload 重新读取文件，内容变化时通知
+func (s *FileSource) reload(onChange func(string)) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		// 替换过程中文件可能短暂不存在，等待下一次事件
+		logger.Warnf("Failed to read app config file %s, keeping current config: %v", s.path, err)
+		return
+	}
+
+	s.mu.Lock()
+	changed := !bytes.Equal(content, s.last)
+	if changed {
+		s.last = content
+	}
+	s.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	logger.Infof("App config file changed: %s", s.path)
+	onChange(string(content))
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testFileDebounce = 50 * time.Millisecond
+
+// configMapDir 按 kubelet 挂载 ConfigMap 的方式组织目录：
+// app.yaml -> ..data/app.yaml，..data -> ..<版本目录>
+type configMapDir struct {
+	t       *testing.T
+	dir     string
+	version int
+	current string
+}
+
+func newConfigMapDir(t *testing.T, content string) *configMapDir {
+	t.Helper()
+	d := &configMapDir{t: t, dir: t.TempDir()}
+	d.swap(content)
+	if err := os.Symlink(filepath.Join("..data", "app.yaml"), d.path()); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func (d *configMapDir) path() string {
+	return filepath.Join(d.dir, "app.yaml")
+}
+
+// swap 写入新的版本目录，原子替换 ..data 链接后删除旧目录
+func (d *configMapDir) swap(content string) {
+	d.t.Helper()
+	d.version++
+	next := fmt.Sprintf("..v%d", d.version)
+	if err := os.Mkdir(filepath.Join(d.dir, next), 0o755); err != nil {
+		d.t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(d.dir, next, "app.yaml"), []byte(content), 0o644); err != nil {
+		d.t.Fatal(err)
+	}
+	tmp := filepath.Join(d.dir, "..data_tmp")
+	if err := os.Symlink(next, tmp); err != nil {
+		d.t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(d.dir, "..data")); err != nil {
+		d.t.Fatal(err)
+	}
+	if d.current != "" {
+		if err := os.RemoveAll(filepath.Join(d.dir, d.current)); err != nil {
+			d.t.Fatal(err)
+		}
+	}
+	d.current = next
+}
+
+// watchFile 启动 FileSource 并返回通知的内容
+func watchFile(t *testing.T, path, want string) <-chan string {
+	t.Helper()
+	src := NewFileSource(path, testFileDebounce)
+	t.Cleanup(func() { _ = src.Close() })
+	content, err := src.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if content != want {
+		t.Fatalf("Load() = %q, want %q", content, want)
+	}
+	changes := make(chan string, 10)
+	if err := src.Watch(func(content string) { changes <- content }); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	return changes
+}
+
+// expectChange 等待一次通知，之后的合并窗口内不应再有通知
+func expectChange(t *testing.T, changes <-chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-changes:
+		if got != want {
+			t.Errorf("onChange content = %q, want %q", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("onChange was not called, want %q", want)
+	}
+	expectNoChange(t, changes)
+}
+
+// expectNoChange 合并窗口过去后没有通知
+func expectNoChange(t *testing.T, changes <-chan string) {
+	t.Helper()
+	select {
+	case got := <-changes:
+		t.Errorf("unexpected onChange with %q", got)
+	case <-time.After(6 * testFileDebounce):
+	}
+}
+
+func TestFileSourceConfigMapSwap(t *testing.T) {
+	d := newConfigMapDir(t, "app:\n  name: v1\n")
+	changes := watchFile(t, d.path(), "app:\n  name: v1\n")
+
+	d.swap("app:\n  name: v2\n")
+	expectChange(t, changes, "app:\n  name: v2\n")
+
+	// 内容未变化的替换不通知
+	d.swap("app:\n  name: v2\n")
+	expectNoChange(t, changes)
+}
+
+func TestFileSourceTemporarilyMissing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(path, []byte("app:\n  name: v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changes := watchFile(t, path, "app:\n  name: v1\n")
+
+	// 文件暂时不存在时保留当前配置
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	expectNoChange(t, changes)
+
+	// 编辑器先写临时文件再 rename
+	tmp := filepath.Join(dir, ".app.yaml.swp")
+	if err := os.WriteFile(tmp, []byte("app:\n  name: v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, changes, "app:\n  name: v2\n")
+}
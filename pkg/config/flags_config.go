@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// 开关类型
+const (
+	FlagTypeBool   = "bool"   // 布尔开关
+	FlagTypeString = "string" // 字符串开关，取值限定在 variants 中
+)
+
+// FlagRule 开关的定向规则，设置了的条件都需要满足，列表中任意一项匹配即可
+type FlagRule struct {
+	Callers    []string          `json:"callers,omitempty" yaml:"callers"`       // 调用方应用名（caller-app）
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes"` // 请求属性（attachment、service、method），所有属性都需要相等
+	Percent    *float64          `json:"percent,omitempty" yaml:"percent"`       // 按用户 ID 哈希选择的比例(0~100)，没有用户 ID 时不匹配
+	Value      interface{}       `json:"value" yaml:"value"`                     // 命中时的取值
+}
+
+// FlagConfig 单个开关的定义，规则按顺序匹配，第一条命中的规则生效，都不命中时取 default
+type FlagConfig struct {
+	Type     string      `json:"type" yaml:"type"`
+	Variants []string    `json:"variants,omitempty" yaml:"variants"` // string 类型的可选取值
+	Default  interface{} `json:"default" yaml:"default"`
+	Rules    []FlagRule  `json:"rules,omitempty" yaml:"rules"`
+}
+
+// GetFlagsConfig 获取开关定义，key 为开关名；定义有误的开关会被跳过并输出警告
+// 返回的 Default 和 Value 已按类型转换为 bool 或 string
+func (m *Manager) GetFlagsConfig() map[string]FlagConfig {
+	flags := make(map[string]FlagConfig)
+
+	flagsMap := m.GetStringMap("flags")
+	if flagsMap == nil {
+		return flags
+	}
+
+	names := make([]string, 0, len(flagsMap))
+	for name := range flagsMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var flag FlagConfig
+		path := "flags." + name
+		err := bindValue(path, flagsMap[name], &flag)
+		if err == nil {
+			err = normalizeFlag(path, &flag)
+		}
+		if err != nil {
+			logger.Warnf("Invalid flag %s, skipping: %v", name, err)
+			continue
+		}
+		flags[name] = flag
+	}
+
+	return flags
+}
+
+// checkFlags 检查配置中所有开关的定义（check-config 使用）
+func checkFlags(data map[string]interface{}) ValidationErrors {
+	flagsMap, _ := data["flags"].(map[string]interface{})
+	names := make([]string, 0, len(flagsMap))
+	for name := range flagsMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs ValidationErrors
+	for _, name := range names {
+		var flag FlagConfig
+		path := "flags." + name
+		err := bindValue(path, flagsMap[name], &flag)
+		if err == nil {
+			err = normalizeFlag(path, &flag)
+		}
+		errs = append(errs, toValidationErrors(path, err)...)
+	}
+	return errs
+}
+
+// normalizeFlag 检查开关定义并把取值转换为对应类型
+func normalizeFlag(path string, flag *FlagConfig) error {
+	var convert func(v interface{}) (interface{}, error)
+	switch flag.Type {
+	case FlagTypeBool:
+		convert = func(v interface{}) (interface{}, error) { return toBool(v) }
+	case FlagTypeString:
+		if len(flag.Variants) == 0 {
+			return ValidationError{Path: path + ".variants", Message: "is required for string flags"}
+		}
+		convert = func(v interface{}) (interface{}, error) {
+			s, err := toString(v)
+			if err != nil {
+				return nil, err
+			}
+			for _, variant := range flag.Variants {
+				if s == variant {
+					return s, nil
+				}
+			}
+			return nil, fmt.Errorf("%q is not one of variants %v", s, flag.Variants)
+		}
+	default:
+		return ValidationError{Path: path + ".type", Message: fmt.Sprintf("unknown flag type %q, expected bool or string", flag.Type)}
+	}
+
+	if flag.Default == nil {
+		return ValidationError{Path: path + ".default", Message: "is required"}
+	}
+	def, err := convert(flag.Default)
+	if err != nil {
+		return ValidationError{Path: path + ".default", Message: err.Error()}
+	}
+	flag.Default = def
+
+	for i := range flag.Rules {
+		rule := &flag.Rules[i]
+		rulePath := fmt.Sprintf("%s.rules[%d]", path, i)
+		if len(rule.Callers) == 0 && len(rule.Attributes) == 0 && rule.Percent == nil {
+			return ValidationError{Path: rulePath, Message: "at least one condition is required"}
+		}
+		if rule.Percent != nil && (*rule.Percent < 0 || *rule.Percent > 100) {
+			return ValidationError{Path: rulePath + ".percent", Message: "must be between 0 and 100"}
+		}
+		if rule.Value == nil {
+			return ValidationError{Path: rulePath + ".value", Message: "is required"}
+		}
+		v, err := convert(rule.Value)
+		if err != nil {
+			return ValidationError{Path: rulePath + ".value", Message: err.Error()}
+		}
+		rule.Value = v
+	}
+	return nil
+}
+
+// GetFlagsConfigFromDubbo 从 dubbo-go 配置中心获取开关定义
+func GetFlagsConfigFromDubbo() map[string]FlagConfig {
+	return defaultManager.GetFlagsConfig()
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeFlag(t *testing.T) {
+	pct := func(p float64) *float64 { return &p }
+	tests := []struct {
+		name string
+		flag FlagConfig
+		want string // 错误信息，为空表示合法
+	}{
+		{name: "bool", flag: FlagConfig{Type: FlagTypeBool, Default: "off", Rules: []FlagRule{{Callers: []string{"a"}, Value: 1}}}},
+		{name: "string", flag: FlagConfig{Type: FlagTypeString, Variants: []string{"x", "y"}, Default: "x", Rules: []FlagRule{{Percent: pct(10), Value: "y"}}}},
+		{name: "unknown type", flag: FlagConfig{Type: "int", Default: 1}, want: `f.type: unknown flag type "int", expected bool or string`},
+		{name: "string without variants", flag: FlagConfig{Type: FlagTypeString, Default: "x"}, want: "f.variants: is required for string flags"},
+		{name: "missing default", flag: FlagConfig{Type: FlagTypeBool}, want: "f.default: is required"},
+		{name: "bad bool default", flag: FlagConfig{Type: FlagTypeBool, Default: "maybe"}, want: "f.default: cannot convert"},
+		{name: "default not a variant", flag: FlagConfig{Type: FlagTypeString, Variants: []string{"x"}, Default: "z"}, want: `f.default: "z" is not one of variants [x]`},
+		{
+			name: "rule value not a variant",
+			flag: FlagConfig{Type: FlagTypeString, Variants: []string{"x"}, Default: "x", Rules: []FlagRule{{Callers: []string{"a"}, Value: "z"}}},
+			want: `f.rules[0].value: "z" is not one of variants [x]`,
+		},
+		{
+			name: "rule without conditions",
+			flag: FlagConfig{Type: FlagTypeBool, Default: false, Rules: []FlagRule{{Value: true}}},
+			want: "f.rules[0]: at least one condition is required",
+		},
+		{
+			name: "rule percent out of range",
+			flag: FlagConfig{Type: FlagTypeBool, Default: false, Rules: []FlagRule{{Percent: pct(120), Value: true}}},
+			want: "f.rules[0].percent: must be between 0 and 100",
+		},
+		{
+			name: "rule without value",
+			flag: FlagConfig{Type: FlagTypeBool, Default: false, Rules: []FlagRule{{Callers: []string{"a"}}}},
+			want: "f.rules[0].value: is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := normalizeFlag("f", &tt.flag)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("normalizeFlag() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("normalizeFlag() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeFlagConvertsValues(t *testing.T) {
+	flag := FlagConfig{Type: FlagTypeBool, Default: "off", Rules: []FlagRule{{Callers: []string{"a"}, Value: int64(1)}}}
+	if err := normalizeFlag("f", &flag); err != nil {
+		t.Fatal(err)
+	}
+	if flag.Default != false || flag.Rules[0].Value != true {
+		t.Errorf("default = %#v, rule value = %#v, want false and true", flag.Default, flag.Rules[0].Value)
+	}
+}
+
+func TestGetFlagsConfigSkipsInvalid(t *testing.T) {
+	m, _ := newTestManager(t, `
+flags:
+  ok:
+    type: string
+    variants: [a, b]
+    default: a
+    rules:
+      - attributes: {region: hz}
+        value: b
+  bad:
+    type: bool
+    default: maybe
+`)
+	got := m.GetFlagsConfig()
+	if _, ok := got["bad"]; ok {
+		t.Error("invalid flag bad was returned")
+	}
+	want := FlagConfig{
+		Type:     FlagTypeString,
+		Variants: []string{"a", "b"},
+		Default:  "a",
+		Rules:    []FlagRule{{Attributes: map[string]string{"region": "hz"}, Value: "b"}},
+	}
+	if !reflect.DeepEqual(got["ok"], want) {
+		t.Errorf("ok = %#v, want %#v", got["ok"], want)
+	}
+
+	errs := checkFlags(m.GetAll())
+	if len(errs) != 1 || errs[0].Path != "flags.bad.default" {
+		t.Errorf("checkFlags() = %v, want one error at flags.bad.default", errs)
+	}
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrKeyNotFound 配置项不存在，GetXxxE 返回的错误可用 errors.Is 判断
+var ErrKeyNotFound = errors.New("config key not found")
+
+// 类型化读取方法，转换规则见 coerce.go：
+//   - GetXxx(key)：不存在或无法转换时返回零值，无法转换时记录警告
+//   - GetXxxOr(key, def)：不存在或无法转换时返回 def
+//   - GetXxxE(key)：不存在返回 ErrKeyNotFound，无法转换返回带 key 的转换错误
+
+// valueReader 提供读取使用的配置树，Manager 和 ConfigSnapshot 都实现
+// 转换函数只读取配置值，列表转换生成新的切片
+type valueReader interface {
+	currentTree() *configTree
+}
+
+// getE 读取并转换配置项
+func getE[T any](r valueReader, key string, conv func(interface{}) (T, error)) (T, error) {
+	return getFrom(r.currentTree(), key, conv)
+}
+
+// getFrom 从配置树读取并转换配置项
+func getFrom[T any](tree *configTree, key string, conv func(interface{}) (T, error)) (T, error) {
+	var zero T
+	val := lookup(tree.data, key)
+	if val == nil {
+		return zero, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	v, err := conv(val)
+	if err != nil {
+		return zero, fmt.Errorf("config key %s: %w", key, err)
+	}
+	return v, nil
+}
+
+// getOr 读取并转换配置项，失败时返回默认值
+// 无法转换时每个版本每个 key 只警告一次，热路径上反复读取不会刷屏
+func getOr[T any](r valueReader, key string, def T, conv func(interface{}) (T, error)) T {
+	tree := r.currentTree()
+	v, err := getFrom(tree, key, conv)
+	if err != nil {
+		if !errors.Is(err, ErrKeyNotFound) && tree.warnOnce(key) {
+			logger.Warnf("Invalid config value in v%d, using default %v: %v", tree.version, def, err)
+		}
+		return def
+	}
+	return v
+}
+
+// get 读取并转换配置项，失败时返回零值
+func get[T any](r valueReader, key string, conv func(interface{}) (T, error)) T {
+	var zero T
+	return getOr(r, key, zero, conv)
+}
+
+// GetString 获取字符串配置，数字和布尔按字面值转换
+func (m *Manager) GetString(key string) string {
+	return get(m, key, toString)
+}
+
+// GetStringOr 获取字符串配置，不存在时返回 def
+func (m *Manager) GetStringOr(key, def string) string {
+	return getOr(m, key, def, toString)
+}
+
+// GetStringE 获取字符串配置，不存在或无法转换时返回错误
+func (m *Manager) GetStringE(key string) (string, error) {
+	return getE(m, key, toString)
+}
+
+// GetInt 获取整数配置，支持数字字符串
+func (m *Manager) GetInt(key string) int {
+	return get(m, key, toInt)
+}
+
+// GetIntOr 获取整数配置，不存在时返回 def
+func (m *Manager) GetIntOr(key string, def int) int {
+	return getOr(m, key, def, toInt)
+}
+
+// GetIntE 获取整数配置，不存在或无法转换时返回错误
+func (m *Manager) GetIntE(key string) (int, error) {
+	return getE(m, key, toInt)
+}
+
+// GetFloat64 获取浮点数配置
+func (m *Manager) GetFloat64(key string) float64 {
+	return get(m, key, toFloat64)
+}
+
+// GetFloat64Or 获取浮点数配置，不存在时返回 def
+func (m *Manager) GetFloat64Or(key string, def float64) float64 {
+	return getOr(m, key, def, toFloat64)
+}
+
+// GetFloat64E 获取浮点数配置，不存在或无法转换时返回错误
+func (m *Manager) GetFloat64E(key string) (float64, error) {
+	return getE(m, key, toFloat64)
+}
+
+// GetBool 获取布尔配置，支持 "true"/"1"/"yes"/"on" 等字符串
+func (m *Manager) GetBool(key string) bool {
+	return get(m, key, toBool)
+}
+
+// GetBoolOr 获取布尔配置，不存在时返回 def
+func (m *Manager) GetBoolOr(key string, def bool) bool {
+	return getOr(m, key, def, toBool)
+}
+
+// GetBoolE 获取布尔配置，不存在或无法转换时返回错误
+func (m *Manager) GetBoolE(key string) (bool, error) {
+	return getE(m, key, toBool)
+}
+
+// GetDuration 获取时长配置，支持 "3s" 或数字（秒）
+func (m *Manager) GetDuration(key string) time.Duration {
+	return get(m, key, toDuration)
+}
+
+// GetDurationOr 获取时长配置，不存在时返回 def
+func (m *Manager) GetDurationOr(key string, def time.Duration) time.Duration {
+	return getOr(m, key, def, toDuration)
+}
+
+// GetDurationE 获取时长配置，不存在或无法转换时返回错误
+func (m *Manager) GetDurationE(key string) (time.Duration, error) {
+	return getE(m, key, toDuration)
+}
+
+// GetTime 获取时间配置，支持 RFC3339、"2006-01-02 15:04:05"、"2006-01-02" 或 Unix 秒
+func (m *Manager) GetTime(key string) time.Time {
+	return get(m, key, toTime)
+}
+
+// GetTimeOr 获取时间配置，不存在时返回 def
+func (m *Manager) GetTimeOr(key string, def time.Time) time.Time {
+	return getOr(m, key, def, toTime)
+}
+
+// GetTimeE 获取时间配置，不存在或无法转换时返回错误
+func (m *Manager) GetTimeE(key string) (time.Time, error) {
+	return getE(m, key, toTime)
+}
+
+// GetStringSlice 获取字符串列表配置，支持 YAML 列表或逗号分隔的字符串
+func (m *Manager) GetStringSlice(key string) []string {
+	return get(m, key, toStringSlice)
+}
+
+// GetStringSliceOr 获取字符串列表配置，不存在时返回 def
+func (m *Manager) GetStringSliceOr(key string, def []string) []string {
+	return getOr(m, key, def, toStringSlice)
+}
+
+// GetStringSliceE 获取字符串列表配置，不存在或无法转换时返回错误
+func (m *Manager) GetStringSliceE(key string) ([]string, error) {
+	return getE(m, key, toStringSlice)
+}
+
+// GetIntSlice 获取整数列表配置，支持 YAML 列表或逗号分隔的字符串
+func (m *Manager) GetIntSlice(key string) []int {
+	return get(m, key, toIntSlice)
+}
+
+// GetIntSliceOr 获取整数列表配置，不存在时返回 def
+func (m *Manager) GetIntSliceOr(key string, def []int) []int {
+	return getOr(m, key, def, toIntSlice)
+}
+
+// GetIntSliceE 获取整数列表配置，不存在或无法转换时返回错误
+func (m *Manager) GetIntSliceE(key string) ([]int, error) {
+	return getE(m, key, toIntSlice)
+}
+
+// 默认管理器的类型化读取方法
+
+// GetString 获取字符串配置，数字和布尔按字面值转换
+func GetString(key string) string { return defaultManager.GetString(key) }
+
+// GetStringOr 获取字符串配置，不存在时返回 def
+func GetStringOr(key, def string) string { return defaultManager.GetStringOr(key, def) }
+
+// GetStringE 获取字符串配置，不存在或无法转换时返回错误
+func GetStringE(key string) (string, error) { return defaultManager.GetStringE(key) }
+
+// GetInt 获取整数配置，支持数字字符串
+func GetInt(key string) int { return defaultManager.GetInt(key) }
+
+// GetIntOr 获取整数配置，不存在时返回 def
+func GetIntOr(key string, def int) int { return defaultManager.GetIntOr(key, def) }
+
+// GetIntE 获取整数配置，不存在或无法转换时返回错误
+func GetIntE(key string) (int, error) { return defaultManager.GetIntE(key) }
+
+// GetFloat64 获取浮点数配置
+func GetFloat64(key string) float64 { return defaultManager.GetFloat64(key) }
+
+// GetFloat64Or 获取浮点数配置，不存在时返回 def
+func GetFloat64Or(key string, def float64) float64 { return defaultManager.GetFloat64Or(key, def) }
+
+// GetFloat64E 获取浮点数配置，不存在或无法转换时返回错误
+func GetFloat64E(key string) (float64, error) { return defaultManager.GetFloat64E(key) }
+
+// GetBool 获取布尔配置，支持 "true"/"1"/"yes"/"on" 等字符串
+func GetBool(key string) bool { return defaultManager.GetBool(key) }
+
+// GetBoolOr 获取布尔配置，不存在时返回 def
+func GetBoolOr(key string, def bool) bool { return defaultManager.GetBoolOr(key, def) }
+
+// GetBoolE 获取布尔配置，不存在或无法转换时返回错误
+func GetBoolE(key string) (bool, error) { return defaultManager.GetBoolE(key) }
+
+// GetDuration 获取时长配置，支持 "3s" 或数字（秒）
+func GetDuration(key string) time.Duration { return defaultManager.GetDuration(key) }
+
+// GetDurationOr 获取时长配置，不存在时返回 def
+func GetDurationOr(key string, def time.Duration) time.Duration {
+	return defaultManager.GetDurationOr(key, def)
+}
+
+// GetDurationE 获取时长配置，不存在或无法转换时返回错误
+func GetDurationE(key string) (time.Duration, error) { return defaultManager.GetDurationE(key) }
+
+// GetTime 获取时间配置，支持 RFC3339、"2006-01-02 15:04:05"、"2006-01-02" 或 Unix 秒
+func GetTime(key string) time.Time { return defaultManager.GetTime(key) }
+
+// GetTimeOr 获取时间配置，不存在时返回 def
+func GetTimeOr(key string, def time.Time) time.Time { return defaultManager.GetTimeOr(key, def) }
+
+// GetTimeE 获取时间配置，不存在或无法转换时返回错误
+func GetTimeE(key string) (time.Time, error) { return defaultManager.GetTimeE(key) }
+
+// GetStringSlice 获取字符串列表配置，支持 YAML 列表或逗号分隔的字符串
+func GetStringSlice(key string) []string { return defaultManager.GetStringSlice(key) }
+
+// GetStringSliceOr 获取字符串列表配置，不存在时返回 def
+func GetStringSliceOr(key string, def []string) []string {
+	return defaultManager.GetStringSliceOr(key, def)
+}
+
+// GetStringSliceE 获取字符串列表配置，不存在或无法转换时返回错误
+func GetStringSliceE(key string) ([]string, error) { return defaultManager.GetStringSliceE(key) }
+
+// GetIntSlice 获取整数列表配置，支持 YAML 列表或逗号分隔的字符串
+func GetIntSlice(key string) []int { return defaultManager.GetIntSlice(key) }
+
+// GetIntSliceOr 获取整数列表配置，不存在时返回 def
+func GetIntSliceOr(key string, def []int) []int { return defaultManager.GetIntSliceOr(key, def) }
+
+// GetIntSliceE 获取整数列表配置，不存在或无法转换时返回错误
+func GetIntSliceE(key string) ([]int, error) { return defaultManager.GetIntSliceE(key) }
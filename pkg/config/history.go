@@ -0,0 +1,316 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 配置来源，记录在 ConfigVersion.Source 中
+const (
+	SourceNacos    = "nacos"
+	SourceRollback = "rollback" // rollback:v3
+	SourcePin      = "pin"      // pin:v3
+	SourceUnpin    = "unpin"
+)
+
+// ConfigVersion 一个配置版本
+type ConfigVersion struct {
+	Version   int64     `json:"version"`
+	Hash      string    `json:"hash"`            // 内容 sha256
+	Source    string    `json:"source"`          // nacos、rollback:v3、pin:v3、unpin
+	Timestamp time.Time `json:"timestamp"`       // 收到配置的时间
+	Applied   bool      `json:"applied"`         // 是否生效过，校验失败或 pin 期间收到的 Nacos 配置为 false
+	Error     string    `json:"error,omitempty"` // 校验失败的原因
+	Content   string    `json:"content,omitempty"`
+}
+
+// HistoryOptions 配置历史选项
+type HistoryOptions struct {
+	Dir  string // 持久化目录，为空时只保存在内存
+	Size int    // 保留的版本数，默认 10
+}
+
+// ErrVersionNotFound 历史中不存在指定版本
+var ErrVersionNotFound = errors.New("config version not found")
+
+// pinFile 固定版本的持久化文件名，重启后仍然生效
+const pinFile = "pin.json"
+
+// configHistory 配置历史：内存保留最近 N 个版本，同时写入磁盘
+type configHistory struct {
+	mu       sync.Mutex
+	opts     HistoryOptions
+	versions []ConfigVersion // 按版本号升序
+	next     int64
+	current  int64          // 当前生效的版本
+	remote   *ConfigVersion // 最近一次从 Nacos 收到的版本，unpin 时恢复
+	pinned   *ConfigVersion // 固定的版本，非 nil 时忽略 Nacos 推送
+}
+
+// newConfigHistory 创建配置历史，从磁盘加载已有版本
+func newConfigHistory(opts HistoryOptions) *configHistory {
+	if opts.Size <= 0 {
+		opts.Size = 10
+	}
+	h := &configHistory{opts: opts, next: 1}
+	if opts.Dir == "" {
+		return h
+	}
+	// 历史版本包含密码等敏感配置，只允许当前用户读写
+	if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+		logger.Errorf("Failed to create config history dir %s: %v, history kept in memory only", opts.Dir, err)
+		h.opts.Dir = ""
+		return h
+	}
+	// 旧版本创建的目录权限为 0755，收紧后已有文件也不再对其他用户可见
+	if err := os.Chmod(opts.Dir, 0o700); err != nil {
+		logger.Warnf("Failed to restrict config history dir %s: %v", opts.Dir, err)
+	}
+	h.load()
+	return h
+}
+
+// load 加载磁盘上的历史版本和固定版本
+func (h *configHistory) load() {
+	files, err := filepath.Glob(filepath.Join(h.opts.Dir, "v*.json"))
+	if err != nil {
+		logger.Errorf("Failed to list config history: %v", err)
+		return
+	}
+	for _, f := range files {
+		var v ConfigVersion
+		if err := readJSONFile(f, &v); err != nil {
+			logger.Warnf("Skip broken config history file %s: %v", f, err)
+			continue
+		}
+		h.versions = append(h.versions, v)
+	}
+	sort.Slice(h.versions, func(i, j int) bool { return h.versions[i].Version < h.versions[j].Version })
+	if n := len(h.versions); n > 0 {
+		h.next = h.versions[n-1].Version + 1
+	}
+	h.prune()
+
+	var pinned ConfigVersion
+	if err := readJSONFile(filepath.Join(h.opts.Dir, pinFile), &pinned); err == nil {
+		h.pinned = &pinned
+		logger.Warnf("Config is pinned to v%d (hash=%s) since %s, Nacos updates are ignored until unpinned",
+			pinned.Version, shortHash(pinned.Hash), pinned.Timestamp.Format(time.RFC3339))
+	} else if !os.IsNotExist(err) {
+		logger.Errorf("Failed to read pinned config: %v", err)
+	}
+}
+
+// record 记录新版本，内容与最新版本相同时复用最新版本
+func (h *configHistory) record(content, source string, applied bool) ConfigVersion {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hash := contentHash(content)
+	if n := len(h.versions); n > 0 && h.versions[n-1].Hash == hash && h.versions[n-1].Source == source {
+		// 重复推送（如重启）复用最新版本
+		v := &h.versions[n-1]
+		if applied {
+			v.Applied = true
+			v.Error = ""
+			h.current = v.Version
+			h.persist(*v)
+		}
+		return *v
+	}
+
+	v := ConfigVersion{
+		Version:   h.next,
+		Hash:      hash,
+		Source:    source,
+		Timestamp: time.Now(),
+		Applied:   applied,
+		Content:   content,
+	}
+	h.next++
+	h.versions = append(h.versions, v)
+	if applied {
+		h.current = v.Version
+	}
+	h.persist(v)
+	h.prune()
+	return v
+}
+
+// markApplied 标记版本已生效
+func (h *configHistory) markApplied(version int64) {
+	h.update(version, func(v *ConfigVersion) {
+		v.Applied = true
+		v.Error = ""
+	})
+	h.setCurrent(version)
+}
+
+// markRejected 标记版本校验失败
+func (h *configHistory) markRejected(version int64, err error) {
+	h.update(version, func(v *ConfigVersion) { v.Error = err.Error() })
+}
+
+// update 修改版本信息并写入磁盘
+func (h *configHistory) update(version int64, fn func(v *ConfigVersion)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.versions {
+		if h.versions[i].Version == version {
+			fn(&h.versions[i])
+			h.persist(h.versions[i])
+			return
+		}
+	}
+}
+
+// lastGood 最近一次生效且未被拒绝的版本，用于启动时配置无效的兜底
+func (h *configHistory) lastGood() (ConfigVersion, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := len(h.versions) - 1; i >= 0; i-- {
+		if v := h.versions[i]; v.Applied && v.Error == "" {
+			return v, true
+		}
+	}
+	return ConfigVersion{}, false
+}
+
+// prune 删除超出保留数量的旧版本
+func (h *configHistory) prune() {
+	for len(h.versions) > h.opts.Size {
+		old := h.versions[0]
+		h.versions = h.versions[1:]
+		if h.opts.Dir != "" {
+			if err := os.Remove(h.versionFile(old.Version)); err != nil && !os.IsNotExist(err) {
+				logger.Warnf("Failed to remove config history v%d: %v", old.Version, err)
+			}
+		}
+	}
+}
+
+// persist 写入磁盘
+func (h *configHistory) persist(v ConfigVersion) {
+	if h.opts.Dir == "" {
+		return
+	}
+	if err := writeJSONFile(h.versionFile(v.Version), v); err != nil {
+		logger.Errorf("Failed to persist config history v%d: %v", v.Version, err)
+	}
+}
+
+// versionFile 版本文件路径
+func (h *configHistory) versionFile(version int64) string {
+	return filepath.Join(h.opts.Dir, fmt.Sprintf("v%08d.json", version))
+}
+
+// get 获取指定版本
+func (h *configHistory) get(version int64) (ConfigVersion, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, v := range h.versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return ConfigVersion{}, fmt.Errorf("%w: v%d", ErrVersionNotFound, version)
+}
+
+// list 返回所有版本（新版本在前）
+func (h *configHistory) list() []ConfigVersion {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]ConfigVersion, len(h.versions))
+	for i, v := range h.versions {
+		out[len(h.versions)-1-i] = v
+	}
+	return out
+}
+
+// setRemote 记录最近一次 Nacos 推送的版本
+func (h *configHistory) setRemote(v ConfigVersion) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remote = &v
+}
+
+// setCurrent 设置当前生效的版本，用于启动时恢复固定版本
+func (h *configHistory) setCurrent(version int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.current = version
+}
+
+// pin 固定版本，nil 表示取消固定
+func (h *configHistory) pin(v *ConfigVersion) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pinned = v
+	if h.opts.Dir == "" {
+		return nil
+	}
+	path := filepath.Join(h.opts.Dir, pinFile)
+	if v == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return writeJSONFile(path, v)
+}
+
+// state 当前版本、固定版本和最近一次 Nacos 推送的版本
+func (h *configHistory) state() (current int64, pinned, remote *ConfigVersion) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.current, h.pinned, h.remote
+}
+
+// contentHash 配置内容的 sha256
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// shortHash 日志中使用的短 hash
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// readJSONFile 读取 JSON 文件
+func readJSONFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSONFile 原子写入 JSON 文件，权限 0600
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// sourceOf 生成带版本号的来源，如 rollback:v3
+func sourceOf(kind string, version int64) string {
+	return fmt.Sprintf("%s:v%d", kind, version)
+}
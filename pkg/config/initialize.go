@@ -2,9 +2,13 @@ package config
 
 import (
 	"context"
+	"time"
+
+	"helloworld/pkg/log"
+	"helloworld/pkg/tracing"
 
-	"github.com/dubbogo/gost/log/logger"
 	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/logging"
 	"gorm.io/gorm"
 )
 
@@ -12,12 +16,14 @@ import (
 type Clients struct {
 	Redis *redis.Client
 	MySQL *gorm.DB
+
+	shutdownTracing func(context.Context) error // 刷新未导出的 span
 }
 
-// InitializeClients 初始化所有客户端连接
-func InitializeClients(appName, group string) (*Clients, error) {
+// InitializeClients 初始化所有客户端连接，应用配置从 dataID、group 读取
+func InitializeClients(appName, dataID, group string) (*Clients, error) {
 	// 初始化应用配置管理器
-	if err := InitAppConfig(appName, group); err != nil {
+	if err := InitAppConfig(dataID, group); err != nil {
 		logger.Errorf("Failed to init app config: %v", err)
 		return nil, err
 	}
@@ -34,6 +40,21 @@ func InitializeClients(appName, group string) (*Clients, error) {
 
 	clients := &Clients{}
 
+	// 初始化链路追踪（需要在 Redis、MySQL 之前，以便安装埋点）
+	tracingCfg := GetTracingConfigFromDubbo()
+	shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName:  appName,
+		Exporter:     tracingCfg.Exporter,
+		Endpoint:     tracingCfg.Endpoint,
+		Protocol:     tracingCfg.Protocol,
+		Insecure:     tracingCfg.Insecure,
+		SamplerRatio: tracingCfg.SamplerRatio,
+	})
+	if err != nil {
+		logger.Errorf("Failed to init tracing: %v", err)
+	}
+	clients.shutdownTracing = shutdown
+
 	// 初始化 Redis
 	redisClient, err := initRedis()
 	if err != nil {
@@ -64,6 +85,12 @@ func initRedis() (*redis.Client, error) {
 		return nil, err
 	}
 
+	// go-redis 内部日志写入 zap
+	redis.SetLogger(log.NewRedisLogger())
+	if level, ok := redisLogLevels[redisCfg.LogLevel]; ok {
+		logging.SetLogLevel(level)
+	}
+
 	redisClient, err := redisCfg.CreateRedisClient()
 	if err != nil {
 		return nil, err
@@ -76,10 +103,20 @@ func initRedis() (*redis.Client, error) {
 		return nil, err
 	}
 
+	redisClient.AddHook(tracing.NewRedisHook(redisCfg.GetAddr()))
+
 	logger.Infof("Redis initialized successfully: %s", redisCfg.GetAddr())
 	return redisClient, nil
 }
 
+// redisLogLevels redis.log_level 到 go-redis 日志级别的映射
+var redisLogLevels = map[string]logging.LogLevelT{
+	"error": logging.LogLevelError,
+	"warn":  logging.LogLevelWarn,
+	"info":  logging.LogLevelInfo,
+	"debug": logging.LogLevelDebug,
+}
+
 // initMySQL 初始化 MySQL 连接
 func initMySQL() (*gorm.DB, error) {
 	mysqlCfg, err := GetMySQLConfigFromDubbo()
@@ -103,6 +140,10 @@ func initMySQL() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	if err := db.Use(tracing.NewGormPlugin(mysqlCfg.Database)); err != nil {
+		logger.Errorf("Failed to install mysql tracing plugin: %v", err)
+	}
+
 	logger.Infof("MySQL initialized successfully: %s@%s:%d/%s",
 		mysqlCfg.Username, mysqlCfg.Host, mysqlCfg.Port, mysqlCfg.Database)
 
@@ -130,5 +171,13 @@ func CloseClients(clients *Clients) {
 		}
 	}
 
+	if clients.shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := clients.shutdownTracing(ctx); err != nil {
+			logger.Errorf("Failed to shutdown tracing: %v", err)
+		}
+	}
+
 	logger.Info("All clients closed")
 }
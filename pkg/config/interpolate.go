@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// 配置值中的占位符，每次加载和更新配置时解析：
+//   - ${redis.host}：引用其他配置项，整个值只有一个占位符时保留被引用值的类型
+//   - ${REDIS_HOST}：配置中不存在该 key 时读取环境变量
+//   - ${REDIS_HOST:127.0.0.1}：配置项和环境变量都不存在时使用默认值，默认值中可以再嵌套占位符
+//   - $${...}：转义，结果为字面量 ${...}
+// 配置项和环境变量都不存在且没有默认值、循环引用、在字符串中引用对象或列表时，返回包含所有问题的 ValidationErrors
+
+// interpolator 解析一次配置中的所有占位符
+type interpolator struct {
+	raw       map[string]interface{}
+	lookupEnv func(string) (string, bool)
+	resolved  map[string]interface{} // 已解析的配置路径
+	stack     []string               // 正在解析的配置路径，用于检测循环引用
+	errs      ValidationErrors
+}
+
+// interpolate 解析配置中的占位符，返回新的配置树，不修改 data
+func interpolate(data map[string]interface{}) (map[string]interface{}, error) {
+	return interpolateWith(data, os.LookupEnv)
+}
+
+// interpolateWith 使用指定的环境变量查找函数解析占位符
+func interpolateWith(data map[string]interface{}, lookupEnv func(string) (string, bool)) (map[string]interface{}, error) {
+	in := &interpolator{
+		raw:       data,
+		lookupEnv: lookupEnv,
+		resolved:  make(map[string]interface{}),
+	}
+	result := in.resolveMap("", data)
+	if len(in.errs) > 0 {
+		sort.SliceStable(in.errs, func(i, j int) bool { return in.errs[i].Path < in.errs[j].Path })
+		return nil, in.errs
+	}
+	return result, nil
+}
+
+// fail 记录解析错误
+func (in *interpolator) fail(path, format string, args ...interface{}) {
+	in.errs = append(in.errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// resolvePath 解析配置路径上的值，结果缓存，同一路径只报告一次错误
+func (in *interpolator) resolvePath(path string, val interface{}) (interface{}, bool) {
+	if v, ok := in.resolved[path]; ok {
+		return v, true
+	}
+	for i, p := range in.stack {
+		if p == path {
+			cycle := append(append([]string{}, in.stack[i:]...), path)
+			in.fail(path, "reference cycle: %s", strings.Join(cycle, " -> "))
+			return val, false
+		}
+	}
+	in.stack = append(in.stack, path)
+	v := in.resolveValue(path, val)
+	in.stack = in.stack[:len(in.stack)-1]
+	in.resolved[path] = v
+	return v, true
+}
+
+// resolveValue 递归解析配置值
+func (in *interpolator) resolveValue(path string, val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		return in.expand(path, v)
+	case map[string]interface{}:
+		return in.resolveMap(path, v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = in.resolveValue(fmt.Sprintf("%s[%d]", path, i), item)
+		}
+		return list
+	}
+	return val
+}
+
+// resolveMap 解析 map 中的每个配置项
+func (in *interpolator) resolveMap(path string, m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k], _ = in.resolvePath(joinPath(path, k), v)
+	}
+	return result
+}
+
+// expand 替换字符串中的占位符
+func (in *interpolator) expand(path, s string) interface{} {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	// 整个值只有一个占位符时保留被引用值的类型，如 port: ${redis.port}
+	if strings.HasPrefix(s, "${") {
+		if end := closingBrace(s, 2); end == len(s)-1 {
+			if v, ok := in.reference(path, s[2:end]); ok {
+				return v
+			}
+			return s
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			b.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		end := closingBrace(s, i+2)
+		if end < 0 {
+			in.fail(path, "unclosed placeholder in %q", s)
+			return s
+		}
+		v, ok := in.reference(path, s[i+2:end])
+		if !ok {
+			return s
+		}
+		str, err := toString(v)
+		if err != nil {
+			in.fail(path, "cannot interpolate %s into string %q", typeName(v), s)
+			return s
+		}
+		b.WriteString(str)
+		i = end + 1
+	}
+	return b.String()
+}
+
+// reference 解析一个占位符：配置项 > 环境变量 > 默认值
+func (in *interpolator) reference(path, expr string) (interface{}, bool) {
+	name, def, hasDef := strings.Cut(expr, ":")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		in.fail(path, "empty placeholder ${%s}", expr)
+		return nil, false
+	}
+	if raw := lookup(in.raw, name); raw != nil {
+		return in.resolvePath(name, raw)
+	}
+	if v, ok := in.lookupEnv(name); ok {
+		return v, true
+	}
+	if hasDef {
+		v := in.expand(path, def)
+		return v, true
+	}
+	in.fail(path, "unresolved reference ${%s}: no such config key or environment variable", name)
+	return nil, false
+}
+
+// closingBrace 返回与 start 之前的 "${" 匹配的 "}" 位置，支持默认值中嵌套占位符，找不到时返回 -1
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
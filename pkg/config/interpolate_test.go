@@ -0,0 +1,234 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeEnv 测试用的环境变量
+func fakeEnv(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	env := fakeEnv(map[string]string{
+		"REDIS_HOST": "10.0.0.1",
+		"EMPTY":      "",
+	})
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		key  string
+		want interface{}
+	}{
+		{
+			name: "config key",
+			data: map[string]interface{}{"redis": map[string]interface{}{"host": "r1"}, "addr": "${redis.host}:6379"},
+			key:  "addr",
+			want: "r1:6379",
+		},
+		{
+			name: "whole value keeps type",
+			data: map[string]interface{}{"redis": map[string]interface{}{"port": 6379}, "port": "${redis.port}"},
+			key:  "port",
+			want: 6379,
+		},
+		{
+			name: "whole value keeps map",
+			data: map[string]interface{}{"base": map[string]interface{}{"x": 1}, "copy": "${base}"},
+			key:  "copy",
+			want: map[string]interface{}{"x": 1},
+		},
+		{
+			name: "embedded number becomes string",
+			data: map[string]interface{}{"redis": map[string]interface{}{"port": 6379}, "addr": "h:${redis.port}"},
+			key:  "addr",
+			want: "h:6379",
+		},
+		{
+			name: "environment variable",
+			data: map[string]interface{}{"host": "${REDIS_HOST}"},
+			key:  "host",
+			want: "10.0.0.1",
+		},
+		{
+			name: "config key wins over environment",
+			data: map[string]interface{}{"REDIS_HOST": "from-config", "v": "${REDIS_HOST}"},
+			key:  "v",
+			want: "from-config",
+		},
+		{
+			name: "empty environment variable is set",
+			data: map[string]interface{}{"v": "${EMPTY:fallback}"},
+			key:  "v",
+			want: "",
+		},
+		{
+			name: "default",
+			data: map[string]interface{}{"host": "${MISSING_HOST:127.0.0.1}"},
+			key:  "host",
+			want: "127.0.0.1",
+		},
+		{
+			name: "default containing colons",
+			data: map[string]interface{}{"addr": "${MISSING_ADDR:127.0.0.1:6379}"},
+			key:  "addr",
+			want: "127.0.0.1:6379",
+		},
+		{
+			name: "default url",
+			data: map[string]interface{}{"url": "${MISSING_URL:http://localhost:8080/path}"},
+			key:  "url",
+			want: "http://localhost:8080/path",
+		},
+		{
+			name: "nested default",
+			data: map[string]interface{}{"host": "${MISSING_A:${REDIS_HOST}}"},
+			key:  "host",
+			want: "10.0.0.1",
+		},
+		{
+			name: "escape",
+			data: map[string]interface{}{"tpl": "$${REDIS_HOST}"},
+			key:  "tpl",
+			want: "${REDIS_HOST}",
+		},
+		{
+			name: "escape next to placeholder",
+			data: map[string]interface{}{"tpl": "$${x} ${REDIS_HOST}"},
+			key:  "tpl",
+			want: "${x} 10.0.0.1",
+		},
+		{
+			name: "transitive reference",
+			data: map[string]interface{}{"a": "${b}", "b": "${c}", "c": "end"},
+			key:  "a",
+			want: "end",
+		},
+		{
+			name: "list item",
+			data: map[string]interface{}{"hosts": []interface{}{"${REDIS_HOST}", "static"}},
+			key:  "hosts",
+			want: []interface{}{"10.0.0.1", "static"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := interpolateWith(tt.data, env)
+			if err != nil {
+				t.Fatalf("interpolate() error = %v", err)
+			}
+			if v := lookup(got, tt.key); !reflect.DeepEqual(v, tt.want) {
+				t.Errorf("%s = %#v, want %#v", tt.key, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterpolateDoesNotModifyInput(t *testing.T) {
+	data := map[string]interface{}{"redis": map[string]interface{}{"host": "${REDIS_HOST}"}}
+	if _, err := interpolateWith(data, fakeEnv(map[string]string{"REDIS_HOST": "h"})); err != nil {
+		t.Fatal(err)
+	}
+	if got := lookup(data, "redis.host"); got != "${REDIS_HOST}" {
+		t.Errorf("input redis.host = %v, want unchanged", got)
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  map[string]interface{}
+		paths []string // 报告错误的配置路径，任一即可
+		msg   string
+	}{
+		{
+			name:  "self reference",
+			data:  map[string]interface{}{"a": "${a}"},
+			paths: []string{"a"},
+			msg:   "reference cycle: a -> a",
+		},
+		{
+			name:  "two key cycle",
+			data:  map[string]interface{}{"a": "${b}", "b": "x-${a}"},
+			paths: []string{"a", "b"},
+			msg:   "reference cycle",
+		},
+		{
+			name:  "nested cycle",
+			data:  map[string]interface{}{"app": map[string]interface{}{"name": "${app.alias}", "alias": "${app.name}"}},
+			paths: []string{"app.name", "app.alias"},
+			msg:   "reference cycle",
+		},
+		{
+			name:  "unresolved without default",
+			data:  map[string]interface{}{"redis": map[string]interface{}{"host": "${NOT_DEFINED}"}},
+			paths: []string{"redis.host"},
+			msg:   "unresolved reference ${NOT_DEFINED}",
+		},
+		{
+			name:  "unresolved inside string",
+			data:  map[string]interface{}{"mysql": map[string]interface{}{"dsn": "user@${NOT_DEFINED}/db"}},
+			paths: []string{"mysql.dsn"},
+			msg:   "unresolved reference ${NOT_DEFINED}",
+		},
+		{
+			name:  "map in string",
+			data:  map[string]interface{}{"base": map[string]interface{}{"x": 1}, "s": "v=${base}"},
+			paths: []string{"s"},
+			msg:   "cannot interpolate object",
+		},
+		{
+			name:  "unclosed",
+			data:  map[string]interface{}{"s": "a ${REDIS_HOST"},
+			paths: []string{"s"},
+			msg:   "unclosed placeholder",
+		},
+		{
+			name:  "empty name",
+			data:  map[string]interface{}{"s": "${:x}"},
+			paths: []string{"s"},
+			msg:   "empty placeholder",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interpolateWith(tt.data, fakeEnv(nil))
+			var errs ValidationErrors
+			if !errors.As(err, &errs) || len(errs) == 0 {
+				t.Fatalf("interpolate() error = %v, want ValidationErrors", err)
+			}
+			e := errs[0]
+			found := false
+			for _, p := range tt.paths {
+				found = found || e.Path == p
+			}
+			if !found {
+				t.Errorf("error path = %q, want one of %v", e.Path, tt.paths)
+			}
+			if !strings.Contains(e.Message, tt.msg) {
+				t.Errorf("error message = %q, want to contain %q", e.Message, tt.msg)
+			}
+		})
+	}
+}
+
+func TestInterpolateReportsAllErrors(t *testing.T) {
+	data := map[string]interface{}{
+		"b": "${MISSING_B}",
+		"a": "${MISSING_A}",
+	}
+	_, err := interpolateWith(data, fakeEnv(nil))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("interpolate() error = %v, want ValidationErrors", err)
+	}
+	if len(errs) != 2 || errs[0].Path != "a" || errs[1].Path != "b" {
+		t.Errorf("errors = %v, want one per path sorted by path", errs)
+	}
+}
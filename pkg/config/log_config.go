@@ -2,49 +2,200 @@ package config
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/dubbogo/gost/log/logger"
+	"helloworld/pkg/log"
+
+	gostlogger "github.com/dubbogo/gost/log/logger"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// logger pkg/config 包日志
+var logger = log.Named("helloworld/pkg/config")
+
 // LogConfig 日志配置
 type LogConfig struct {
-	Level    string // 日志级别: debug, info, warn, error, fatal
-	Filename string // 日志文件路径
-	MaxSize  int    // 单个日志文件最大大小(MB)
-	MaxAge   int    // 日志文件保留天数
+	Level          string             // 日志级别: debug, info, warn, error, fatal
+	Levels         map[string]string  // 按包设置级别，key 为 logger 名称，如 "helloworld/pkg/config"、"gorm"
+	Outputs        []LogOutputConfig  // 输出列表，未配置时使用 stdout + filename 的默认输出
+	Filename       string             // 日志文件路径（未配置 outputs 时生效）
+	MaxSize        int                // 单个日志文件最大大小(MB)
+	MaxAge         int                // 日志文件保留天数
+	MaxBackups     int                // 最多保留的备份文件数
+	Compress       bool               // 是否压缩旧文件
+	Sampling       LogSamplingConfig  // 日志采样
+	ErrorRateLimit LogRateLimitConfig // 重复错误限频
+	Access         AccessLogConfig    // RPC 访问日志
 }
 
-// GetLogConfigFromNacos 从 nacos 获取日志配置
-func GetLogConfigFromNacos() (*LogConfig, error) {
+// LogOutputConfig 单个日志输出配置
+type LogOutputConfig struct {
+	Type       string `yaml:"type"`        // stdout, stderr, file, rotating_file, syslog, udp
+	Encoder    string `yaml:"encoder"`     // json, console, logfmt，默认 stdout/stderr 为 console，其余为 json
+	Level      string `yaml:"level"`       // 该输出的级别下限，为空时不额外过滤
+	Filename   string `yaml:"filename"`    // file / rotating_file 的文件路径
+	Network    string `yaml:"network"`     // syslog 网络类型：udp、tcp，为空时写本机 syslog
+	Address    string `yaml:"address"`     // syslog / udp 地址，如 127.0.0.1:514
+	Tag        string `yaml:"tag"`         // syslog tag，默认进程名
+	MaxSize    int    `yaml:"max_size"`    // 单个文件最大大小(MB)
+	MaxAge     int    `yaml:"max_age"`     // 保留天数
+	MaxBackups int    `yaml:"max_backups"` // 最多保留的备份文件数
+	Compress   bool   `yaml:"compress"`    // 是否压缩旧文件
+	Daily      bool   `yaml:"daily"`       // 是否每天零点轮转
+	LocalTime  bool   `yaml:"local_time"`  // 备份文件名和零点使用本地时间
+}
+
+// LogSamplingConfig 日志采样配置：每个 tick 内相同级别、相同消息的日志先输出 initial 条，之后每 thereafter 条输出 1 条
+type LogSamplingConfig struct {
+	Enabled    bool
+	Tick       time.Duration
+	Initial    int
+	Thereafter int
+	Levels     map[string]LogSamplingRule // 按级别覆盖，initial 为 0 表示该级别不采样
+}
+
+// LogSamplingRule 单个级别的采样规则
+type LogSamplingRule struct {
+	Initial    int `yaml:"initial"`
+	Thereafter int `yaml:"thereafter"`
+}
+
+// LogRateLimitConfig 重复错误限频：同一消息的 error 日志每个 interval 最多输出 burst 条
+type LogRateLimitConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	Burst    int
+}
+
+// AccessLogConfig RPC 访问日志配置
+type AccessLogConfig struct {
+	Enabled    bool               // 是否记录访问日志
+	Filename   string             // 访问日志文件路径，为空时输出到 stdout
+	MaxSize    int                // 单个日志文件最大大小(MB)
+	MaxAge     int                // 日志文件保留天数
+	MaxBackups int                // 最多保留的备份文件数
+	Sampling   map[string]float64 // 按方法采样比例(0~1)，key 为 "方法名" 或 "接口名.方法名"，失败的请求始终记录
+}
+
+// defaultLogLevel 应用配置中没有 log.level 时的级别，来自 -log-level 或 profile
+var defaultLogLevel = "info"
+
+// SetDefaultLogLevel 设置应用配置中没有 log.level 时的日志级别，需要在 InitializeClients 之前调用
+func SetDefaultLogLevel(level string) {
+	if level != "" {
+		defaultLogLevel = level
+	}
+}
+
+// GetLogConfig 获取日志配置
+func (m *Manager) GetLogConfig() (*LogConfig, error) {
 	cfg := &LogConfig{
-		Level:    "info", // 默认级别
-		Filename: "",
-		MaxSize:  100, // 默认 100MB
-		MaxAge:   30,  // 默认保留 30 天
+		Level:      defaultLogLevel, // 默认级别
+		Filename:   "",
+		MaxSize:    100,  // 默认 100MB
+		MaxAge:     30,   // 默认保留 30 天
+		MaxBackups: 10,   // 默认保留 10 个备份
+		Compress:   true, // 默认压缩旧文件
+		Levels:     make(map[string]string),
+		Sampling: LogSamplingConfig{
+			Tick:       time.Second,
+			Initial:    100,
+			Thereafter: 100,
+			Levels:     make(map[string]LogSamplingRule),
+		},
+		ErrorRateLimit: LogRateLimitConfig{
+			Interval: time.Minute,
+			Burst:    5,
+		},
+		Access: AccessLogConfig{
+			Enabled:    true,
+			MaxSize:    100,
+			MaxAge:     7,
+			MaxBackups: 10,
+			Sampling:   make(map[string]float64),
+		},
 	}
 
 	// 从 nacos 配置中读取日志配置
-	if IsSet("log.level") {
-		cfg.Level = GetString("log.level")
+	cfg.Level = m.GetStringOr("log.level", cfg.Level)
+	cfg.Filename = m.GetStringOr("log.filename", cfg.Filename)
+	cfg.MaxSize = m.GetIntOr("log.max_size", cfg.MaxSize)
+	cfg.MaxAge = m.GetIntOr("log.max_age", cfg.MaxAge)
+	cfg.MaxBackups = m.GetIntOr("log.max_backups", cfg.MaxBackups)
+	cfg.Compress = m.GetBoolOr("log.compress", cfg.Compress)
+	if outputs, ok := m.Get("log.outputs").([]interface{}); ok {
+		for i, v := range outputs {
+			om, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("log.outputs[%d] must be a map", i)
+			}
+			o, err := parseLogOutput(fmt.Sprintf("log.outputs[%d]", i), om)
+			if err != nil {
+				return nil, err
+			}
+			cfg.Outputs = append(cfg.Outputs, o)
+		}
 	}
-	if IsSet("log.filename") {
-		cfg.Filename = GetString("log.filename")
+	if m.IsSet("log.levels") {
+		if err := m.Bind("log.levels", &cfg.Levels); err != nil {
+			return nil, err
+		}
 	}
-	if IsSet("log.max_size") {
-		cfg.MaxSize = GetInt("log.max_size")
+
+	// 组件日志级别，log.levels 中显式配置的优先
+	// GORM 的 SQL 按 debug 输出，mysql.log_level: info 对应 gorm logger 的 debug
+	if _, ok := cfg.Levels[log.GormLoggerName]; !ok && m.IsSet("mysql.log_level") {
+		level := m.GetString("mysql.log_level")
+		if level == "info" {
+			level = "debug"
+		}
+		cfg.Levels[log.GormLoggerName] = level
 	}
-	if IsSet("log.max_age") {
-		cfg.MaxAge = GetInt("log.max_age")
+	if _, ok := cfg.Levels[log.RedisLoggerName]; !ok && m.IsSet("redis.log_level") {
+		cfg.Levels[log.RedisLoggerName] = m.GetString("redis.log_level")
+	}
+
+	// 采样配置
+	cfg.Sampling.Enabled = m.GetBoolOr("log.sampling.enabled", cfg.Sampling.Enabled)
+	cfg.Sampling.Tick = m.GetDurationOr("log.sampling.tick", cfg.Sampling.Tick)
+	cfg.Sampling.Initial = m.GetIntOr("log.sampling.initial", cfg.Sampling.Initial)
+	cfg.Sampling.Thereafter = m.GetIntOr("log.sampling.thereafter", cfg.Sampling.Thereafter)
+	for level, v := range m.GetStringMap("log.sampling.levels") {
+		rule := LogSamplingRule{Initial: cfg.Sampling.Initial, Thereafter: cfg.Sampling.Thereafter}
+		if err := bindValue("log.sampling.levels."+level, v, &rule); err != nil {
+			return nil, err
+		}
+		cfg.Sampling.Levels[level] = rule
+	}
+
+	// 重复错误限频
+	cfg.ErrorRateLimit.Enabled = m.GetBoolOr("log.error_rate_limit.enabled", cfg.ErrorRateLimit.Enabled)
+	cfg.ErrorRateLimit.Interval = m.GetDurationOr("log.error_rate_limit.interval", cfg.ErrorRateLimit.Interval)
+	cfg.ErrorRateLimit.Burst = m.GetIntOr("log.error_rate_limit.burst", cfg.ErrorRateLimit.Burst)
+
+	// 访问日志配置
+	cfg.Access.Enabled = m.GetBoolOr("log.access.enabled", cfg.Access.Enabled)
+	cfg.Access.Filename = m.GetStringOr("log.access.filename", cfg.Access.Filename)
+	cfg.Access.MaxSize = m.GetIntOr("log.access.max_size", cfg.Access.MaxSize)
+	cfg.Access.MaxAge = m.GetIntOr("log.access.max_age", cfg.Access.MaxAge)
+	cfg.Access.MaxBackups = m.GetIntOr("log.access.max_backups", cfg.Access.MaxBackups)
+	if m.IsSet("log.access.sampling") {
+		if err := m.Bind("log.access.sampling", &cfg.Access.Sampling); err != nil {
+			return nil, err
+		}
 	}
 
 	return cfg, nil
 }
 
+// GetLogConfigFromNacos 从 nacos 获取日志配置
+func GetLogConfigFromNacos() (*LogConfig, error) {
+	return defaultManager.GetLogConfig()
+}
+
 // InitLogger 初始化日志系统
 func InitLogger(cfg *LogConfig) error {
 	if cfg == nil {
@@ -52,86 +203,218 @@ func InitLogger(cfg *LogConfig) error {
 	}
 
 	// 1. 设置日志级别
-	if !logger.SetLoggerLevel(cfg.Level) {
+	if !gostlogger.SetLoggerLevel(cfg.Level) {
 		return fmt.Errorf("invalid log level: %s", cfg.Level)
 	}
 
-	// 2. 如果配置了日志文件，设置文件输出
-	if cfg.Filename != "" {
-		// 确保日志目录存在
-		logDir := filepath.Dir(cfg.Filename)
-		if err := os.MkdirAll(logDir, 0755); err != nil {
-			return fmt.Errorf("failed to create log directory: %w", err)
+	// 2. 解析日志策略（级别、采样、限频），配置错误时不替换输出
+	policy, err := buildLogPolicy(cfg)
+	if err != nil {
+		return err
+	}
+
+	// 3. 创建输出，未配置 outputs 时保持原有行为：stdout 控制台 + filename 轮转 JSON 文件
+	// 输出 core 的级别下限由各输出单独配置，包级别由 policy 过滤
+	outputs, err := buildLogOutputs(cfg)
+	if err != nil {
+		return err
+	}
+	core, closeOutputs, err := log.NewOutputs(outputs)
+	if err != nil {
+		return err
+	}
+
+	// 4. 替换输出和策略，已创建的命名 logger 立即生效
+	// 新的 core 发布之后再关闭旧的输出，关闭时会等待经由旧 core 正在进行的写入完成
+	log.SetOutput(core)
+	log.Configure(policy)
+	outputsMu.Lock()
+	if closePrevOutputs != nil {
+		if err := closePrevOutputs(); err != nil {
+			logger.Warnf("Failed to close previous log outputs: %v", err)
 		}
+	}
+	closePrevOutputs = closeOutputs
+	outputsMu.Unlock()
+
+	// 设置全局 logger（通过 gost logger 包装调用，需要跳过一层调用栈）
+	gostlogger.SetLogger(log.Root().WithOptions(zap.AddCallerSkip(1)).Sugar())
 
-		// 使用 lumberjack 实现日志轮转
-		fileWriter := &lumberjack.Logger{
-			Filename:   cfg.Filename,
-			MaxSize:    cfg.MaxSize, // MB
-			MaxBackups: 10,          // 最多保留10个备份文件
-			MaxAge:     cfg.MaxAge,  // 保留天数
-			Compress:   true,        // 压缩旧文件
-			LocalTime:  true,        // 使用本地时间
+	// 5. 监听配置变化，热更新级别、采样和限频
+	watchLogConfigOnce.Do(func() {
+		RegisterChangeListener(func(map[string]interface{}) {
+			reloadLogPolicy()
+		})
+	})
+
+	logger.Infof("Logger initialized: level=%s, levels=%v, outputs=%s, sampling=%v, error_rate_limit=%v",
+		cfg.Level, cfg.Levels, describeLogOutputs(outputs), cfg.Sampling.Enabled, cfg.ErrorRateLimit.Enabled)
+
+	return nil
+}
+
+var (
+	// watchLogConfigOnce 保证只注册一次日志配置监听
+	watchLogConfigOnce sync.Once
+
+	// closePrevOutputs 关闭上一次 InitLogger 打开的文件和连接
+	outputsMu        sync.Mutex
+	closePrevOutputs func() error
+)
+
+// buildLogOutputs 将输出配置转换为 log.Output
+func buildLogOutputs(cfg *LogConfig) ([]log.Output, error) {
+	if len(cfg.Outputs) == 0 {
+		outputs := []log.Output{{Type: log.OutputStdout, Encoder: log.EncoderConsole}}
+		if cfg.Filename != "" {
+			outputs = append(outputs, log.Output{
+				Type:     log.OutputRotatingFile,
+				Encoder:  log.EncoderJSON,
+				Filename: cfg.Filename,
+				Rotation: log.Rotation{
+					MaxSize:    cfg.MaxSize,
+					MaxAge:     cfg.MaxAge,
+					MaxBackups: cfg.MaxBackups,
+					Compress:   cfg.Compress,
+					LocalTime:  true,
+				},
+			})
 		}
+		return outputs, nil
+	}
 
-		// 解析日志级别
-		var zapLevel zapcore.Level
-		switch cfg.Level {
-		case "debug":
-			zapLevel = zapcore.DebugLevel
-		case "info":
-			zapLevel = zapcore.InfoLevel
-		case "warn":
-			zapLevel = zapcore.WarnLevel
-		case "error":
-			zapLevel = zapcore.ErrorLevel
-		case "fatal":
-			zapLevel = zapcore.FatalLevel
-		default:
-			zapLevel = zapcore.InfoLevel
+	outputs := make([]log.Output, 0, len(cfg.Outputs))
+	for i, o := range cfg.Outputs {
+		level := zapcore.DebugLevel
+		if o.Level != "" {
+			lvl, err := parseLogLevel(o.Level)
+			if err != nil {
+				return nil, fmt.Errorf("log.outputs[%d].level: %w", i, err)
+			}
+			level = lvl
 		}
+		outputs = append(outputs, log.Output{
+			Type:     o.Type,
+			Encoder:  o.Encoder,
+			Level:    level,
+			Filename: o.Filename,
+			Network:  o.Network,
+			Address:  o.Address,
+			Tag:      o.Tag,
+			Rotation: log.Rotation{
+				MaxSize:    o.MaxSize,
+				MaxAge:     o.MaxAge,
+				MaxBackups: o.MaxBackups,
+				Compress:   o.Compress,
+				Daily:      o.Daily,
+				LocalTime:  o.LocalTime,
+			},
+		})
+	}
+	return outputs, nil
+}
+
+// parseLogOutput 解析 log.outputs 中的单个输出
+func parseLogOutput(path string, m map[string]interface{}) (LogOutputConfig, error) {
+	o := LogOutputConfig{LocalTime: true}
+	if err := bindValue(path, m, &o); err != nil {
+		return LogOutputConfig{}, err
+	}
+	return o, nil
+}
 
-		// 创建 encoder 配置
-		encoderConfig := zapcore.EncoderConfig{
-			TimeKey:        "time",
-			LevelKey:       "level",
-			NameKey:        "logger",
-			CallerKey:      "caller",
-			MessageKey:     "msg",
-			StacktraceKey:  "stacktrace",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    zapcore.CapitalLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.SecondsDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
+// describeLogOutputs 输出列表的简要描述，用于启动日志
+func describeLogOutputs(outputs []log.Output) string {
+	parts := make([]string, 0, len(outputs))
+	for _, o := range outputs {
+		desc := o.Type
+		switch {
+		case o.Filename != "":
+			desc += "(" + o.Filename + ")"
+		case o.Address != "":
+			desc += "(" + o.Address + ")"
 		}
+		parts = append(parts, desc)
+	}
+	return strings.Join(parts, ",")
+}
 
-		// 创建文件 core
-		fileCore := zapcore.NewCore(
-			zapcore.NewJSONEncoder(encoderConfig),
-			zapcore.AddSync(fileWriter),
-			zapLevel,
-		)
+// reloadLogPolicy 配置变化后重新加载日志策略，输出目标不变
+func reloadLogPolicy() {
+	cfg, err := GetLogConfigFromNacos()
+	if err != nil {
+		logger.Errorf("Failed to reload log config: %v", err)
+		return
+	}
+	policy, err := buildLogPolicy(cfg)
+	if err != nil {
+		logger.Errorf("Invalid log config, keeping current policy: %v", err)
+		return
+	}
+	log.Configure(policy)
+	logger.Infof("Log policy reloaded: level=%s, levels=%v, sampling=%v, error_rate_limit=%v",
+		cfg.Level, cfg.Levels, cfg.Sampling.Enabled, cfg.ErrorRateLimit.Enabled)
+}
 
-		// 创建控制台 core (保留控制台输出)
-		consoleCore := zapcore.NewCore(
-			zapcore.NewConsoleEncoder(encoderConfig),
-			zapcore.AddSync(os.Stdout),
-			zapLevel,
-		)
+// buildLogPolicy 将日志配置转换为 log.Policy
+func buildLogPolicy(cfg *LogConfig) (log.Policy, error) {
+	level, err := parseLogLevel(cfg.Level)
+	if err != nil {
+		return log.Policy{}, err
+	}
+	policy := log.Policy{Level: level, Levels: make(map[string]zapcore.Level, len(cfg.Levels))}
 
-		// 合并两个 core
-		core := zapcore.NewTee(fileCore, consoleCore)
+	for name, l := range cfg.Levels {
+		lvl, err := parseLogLevel(l)
+		if err != nil {
+			return log.Policy{}, fmt.Errorf("log.levels.%s: %w", name, err)
+		}
+		policy.Levels[name] = lvl
+	}
 
-		// 创建新的 logger
-		zapLogger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	if cfg.Sampling.Enabled {
+		sampling := &log.SamplingPolicy{
+			Tick:       cfg.Sampling.Tick,
+			Initial:    cfg.Sampling.Initial,
+			Thereafter: cfg.Sampling.Thereafter,
+			Levels:     make(map[zapcore.Level]log.SamplingRule, len(cfg.Sampling.Levels)),
+		}
+		for l, rule := range cfg.Sampling.Levels {
+			lvl, err := parseLogLevel(l)
+			if err != nil {
+				return log.Policy{}, fmt.Errorf("log.sampling.levels.%s: %w", l, err)
+			}
+			sampling.Levels[lvl] = log.SamplingRule{Initial: rule.Initial, Thereafter: rule.Thereafter}
+		}
+		policy.Sampling = sampling
+	}
 
-		// 设置全局 logger
-		logger.SetLogger(zapLogger.Sugar())
+	if cfg.ErrorRateLimit.Enabled {
+		policy.ErrorRateLimit = &log.RateLimitPolicy{
+			Interval: cfg.ErrorRateLimit.Interval,
+			Burst:    cfg.ErrorRateLimit.Burst,
+		}
 	}
 
-	logger.Infof("Logger initialized: level=%s, file=%s, max_size=%dMB, max_age=%d days",
-		cfg.Level, cfg.Filename, cfg.MaxSize, cfg.MaxAge)
+	return policy, nil
+}
 
-	return nil
+// parseLogLevel 解析日志级别
+func parseLogLevel(level string) (zapcore.Level, error) {
+	switch level {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "warn":
+		return zapcore.WarnLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	case "fatal":
+		return zapcore.FatalLevel, nil
+	case "off", "silent":
+		return log.OffLevel, nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("invalid log level: %s", level)
+	}
 }
@@ -0,0 +1,298 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Manager 应用配置管理器：从 Source 加载配置，校验通过后生效，记录版本历史并通知监听者
+// 包级函数（Get、GetInt、GetRedisConfigFromDubbo 等）使用默认 Manager，见 Default
+type Manager struct {
+	source   Source
+	format   string       // 配置格式，为空时由 Source 的扩展名决定，见 RegisterDecoder
+	instance InstanceInfo // 当前实例的标签，用于匹配 overrides
+
+	// tree 当前生效的配置树，发布后不再修改；读取无锁，更新时整体替换
+	tree atomic.Pointer[configTree]
+
+	mu        sync.Mutex
+	listeners []ChangeListener
+	auditors  []ChangeAuditor
+
+	// notifyMu 串行化监听者通知，notified 为最近一次通知的配置树
+	notifyMu sync.Mutex
+	notified *configTree
+
+	// remoteMu 串行化配置来源的推送；推送的校验在 applyMu 之外执行
+	remoteMu sync.Mutex
+
+	// applyMu 串行化所有生效配置的路径：配置来源推送、灰度生效、回滚、固定和取消固定
+	// 固定状态和当前版本在持有 applyMu 时读取，避免操作之间交错
+	// 持有 applyMu 时只发布配置树，校验器试连和监听者回调都在锁外执行
+	applyMu    sync.Mutex
+	history    *configHistory
+	reloadOpts ReloadOptions
+
+	validatorsMu sync.RWMutex
+	validators   []namedValidator
+
+	// 灰度等待中的配置，新的推送会替代尚未生效的配置
+	pendingMu      sync.Mutex
+	pendingTimer   *time.Timer
+	pendingVersion int64
+}
+
+// AppConfigManager 旧名称
+//
+// Deprecated: 使用 Manager
+type AppConfigManager = Manager
+
+// ChangeListener 配置变化回调，参数为更新后的完整配置（副本）
+type ChangeListener func(data map[string]interface{})
+
+// configTree 不可变的配置树
+type configTree struct {
+	data      map[string]interface{}
+	raw       map[string]interface{} // 合并 overrides 之后、解析占位符之前的配置，审计 diff 使用，不包含环境变量的值
+	version   int64
+	hash      string   // 配置内容的 sha256
+	overrides []string // 命中的 overrides id
+
+	// warned 已输出过转换警告的 key，每个版本每个 key 只警告一次
+	warned sync.Map
+}
+
+// warnOnce key 在该版本中第一次调用时返回 true
+func (t *configTree) warnOnce(key string) bool {
+	_, loaded := t.warned.LoadOrStore(key, struct{}{})
+	return !loaded
+}
+
+// ManagerOption Manager 选项
+type ManagerOption func(m *Manager)
+
+// WithHistory 设置配置历史的持久化目录和保留数量，默认只在内存保留 10 个版本
+func WithHistory(opts HistoryOptions) ManagerOption {
+	return func(m *Manager) {
+		m.history = newConfigHistory(opts)
+	}
+}
+
+// WithReloadOptions 设置配置更新选项
+func WithReloadOptions(opts ReloadOptions) ManagerOption {
+	return func(m *Manager) {
+		m.reloadOpts = normalizeReloadOptions(opts)
+	}
+}
+
+// WithFormat 设置配置格式（yaml、json、properties、toml 或 RegisterDecoder 注册的格式）
+// 不设置时按 Source 的 data ID 或文件扩展名判断，没有扩展名时为 yaml
+func WithFormat(format string) ManagerOption {
+	return func(m *Manager) {
+		m.format = format
+	}
+}
+
+// WithInstance 设置匹配 overrides 使用的实例标签，默认为 DetectInstance 的结果
+func WithInstance(info InstanceInfo) ManagerOption {
+	return func(m *Manager) {
+		m.instance = info
+	}
+}
+
+// WithoutDefaultValidators 不注册内置校验器（profile、schema、flags、redis、mysql），用于测试
+func WithoutDefaultValidators() ManagerOption {
+	return func(m *Manager) {
+		m.validators = nil
+	}
+}
+
+// NewManager 创建配置管理器，调用 Start 后开始加载和监听配置
+func NewManager(source Source, opts ...ManagerOption) *Manager {
+	m := &Manager{
+		source:     source,
+		reloadOpts: normalizeReloadOptions(ReloadOptions{}),
+		validators: defaultValidators(),
+		instance:   DetectInstance(),
+	}
+	m.tree.Store(&configTree{data: make(map[string]interface{}), raw: make(map[string]interface{})})
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.history == nil {
+		m.history = newConfigHistory(HistoryOptions{})
+	}
+	return m
+}
+
+// Start 加载配置并开始监听变化
+func (m *Manager) Start() error {
+	if m.source == nil {
+		return fmt.Errorf("config source is nil")
+	}
+	if m.format == "" {
+		m.format = FormatYAML
+		if fs, ok := m.source.(formatSource); ok {
+			m.format = fs.Format()
+		}
+	}
+	if _, err := lookupDecoder(m.format); err != nil {
+		return err
+	}
+
+	// 获取配置内容
+	content, err := m.source.Load()
+	if err != nil {
+		logger.Errorf("Failed to get config from %s: %v", m.source.Name(), err)
+		return err
+	}
+
+	// 解析并应用配置，同时记录到配置历史
+	if err := m.applyRemote(content); err != nil {
+		return err
+	}
+
+	// 校验或解析失败时保留当前配置
+	return m.source.Watch(func(content string) {
+		_ = m.applyRemote(content)
+	})
+}
+
+// RegisterChangeListener 注册配置变化回调，配置更新成功后按注册顺序调用
+func (m *Manager) RegisterChangeListener(l ChangeListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, l)
+}
+
+// notifyChangeListeners 把当前生效的配置通知给所有配置变化回调，在释放 applyMu 之后调用
+// 并发的生效操作可能先后调用，每次都通知调用时的最新配置，已通知过的配置不重复通知，
+// 监听者最后收到的总是最新配置
+func (m *Manager) notifyChangeListeners() {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+
+	tree := m.tree.Load()
+	if tree == m.notified || tree.version == 0 {
+		return
+	}
+	m.notified = tree
+
+	m.mu.Lock()
+	listeners := make([]ChangeListener, len(m.listeners))
+	copy(listeners, m.listeners)
+	m.mu.Unlock()
+
+	for _, l := range listeners {
+		l(deepCopyMap(tree.data))
+	}
+}
+
+// parseConfig 按配置格式解析配置内容，合并命中的 overrides，并解析 ${...} 占位符
+func (m *Manager) parseConfig(content string) (*configTree, error) {
+	tree, err := m.buildTree(content)
+	if err != nil {
+		logger.Errorf("Failed to parse config: %v", err)
+		return nil, err
+	}
+	return tree, nil
+}
+
+// buildTree 解析配置内容，生成尚未发布的配置树
+func (m *Manager) buildTree(content string) (*configTree, error) {
+	data, err := decodeConfig(m.format, []byte(content))
+	if err != nil {
+		return nil, err
+	}
+	raw, matched, err := applyOverrides(data, m.instance)
+	if err != nil {
+		return nil, err
+	}
+	data, err = interpolate(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &configTree{data: data, raw: raw, overrides: matched}, nil
+}
+
+// applyConfig 发布新的配置树，tree 发布后不能再修改；调用方持有 applyMu，释放后调用 notifyChangeListeners
+func (m *Manager) applyConfig(tree *configTree, v ConfigVersion) {
+	tree.version = v.Version
+	tree.hash = v.Hash
+	m.audit(v, AuditApplied, tree, nil)
+	m.tree.Store(tree)
+	setConfigInfo(v)
+
+	if len(tree.overrides) > 0 {
+		logger.Infof("App config v%d applied: source=%s, hash=%s, overrides=%v",
+			v.Version, v.Source, shortHash(v.Hash), tree.overrides)
+	} else {
+		logger.Infof("App config v%d applied: source=%s, hash=%s", v.Version, v.Source, shortHash(v.Hash))
+	}
+}
+
+// Get 获取配置值（支持点号路径，如 "redis.host"），无锁读取，不复制
+// 返回的 map 和切片与生效的配置共享，只能读取；需要修改时使用 GetStringMap、GetAll 或 Bind
+// 多次调用之间配置可能已更新，需要一致的多个值时使用 Snapshot
+func (m *Manager) Get(key string) interface{} {
+	return lookup(m.tree.Load().data, key)
+}
+
+// currentTree 当前生效的配置树
+func (m *Manager) currentTree() *configTree {
+	return m.tree.Load()
+}
+
+// lookup 按点号路径查找配置值
+func lookup(data map[string]interface{}, key string) interface{} {
+	var current interface{} = data
+	for _, k := range splitKey(key) {
+		cm, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = cm[k]
+	}
+	return current
+}
+
+// maxKeyPaths 缓存的 key 路径上限，key 一般是代码中的常量，超过上限说明在拼接动态 key，不再缓存
+const maxKeyPaths = 10000
+
+var (
+	keyPaths     sync.Map // key -> []string
+	keyPathCount atomic.Int64
+)
+
+// splitKey 拆分点号路径，结果缓存后只读
+func splitKey(key string) []string {
+	if v, ok := keyPaths.Load(key); ok {
+		return v.([]string)
+	}
+	parts := strings.Split(key, ".")
+	if keyPathCount.Load() < maxKeyPaths {
+		if _, loaded := keyPaths.LoadOrStore(key, parts); !loaded {
+			keyPathCount.Add(1)
+		}
+	}
+	return parts
+}
+
+// GetStringMap 获取map配置，返回副本
+func (m *Manager) GetStringMap(key string) map[string]interface{} {
+	sm, _ := m.Get(key).(map[string]interface{})
+	return deepCopyMap(sm)
+}
+
+// IsSet 检查配置是否存在
+func (m *Manager) IsSet(key string) bool {
+	return lookup(m.tree.Load().data, key) != nil
+}
+
+// GetAll 获取所有配置的深拷贝
+func (m *Manager) GetAll() map[string]interface{} {
+	return deepCopyMap(m.tree.Load().data)
+}
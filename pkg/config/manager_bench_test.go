@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"helloworld/pkg/log"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newBenchManager 创建使用内存配置来源的管理器，不执行内置校验器；丢弃每次更新的日志
+func newBenchManager(b *testing.B) (*Manager, *MemorySource) {
+	b.Helper()
+	log.SetOutput(zapcore.NewNopCore())
+	src := NewMemorySource(benchConfig(0))
+	m := NewManager(src, WithoutDefaultValidators())
+	if err := m.Start(); err != nil {
+		b.Fatal(err)
+	}
+	return m, src
+}
+
+// benchConfig 第 i 次推送的配置内容，每次内容不同以触发完整的更新流程
+func benchConfig(i int) string {
+	return fmt.Sprintf(`{"app":{"name":"bench","greeting":"hello-%d"},"server":{"port":%d}}`, i, 20000+i%1000)
+}
+
+// startPushing 在后台持续推送新配置，返回停止函数
+func startPushing(src *MemorySource) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			src.Set(benchConfig(i))
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func BenchmarkManagerGetString(b *testing.B) {
+	m, src := newBenchManager(b)
+	stop := startPushing(src)
+	defer stop()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if m.GetString("app.greeting") == "" {
+				b.Error("app.greeting is empty")
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkManagerGetInt(b *testing.B) {
+	m, src := newBenchManager(b)
+	stop := startPushing(src)
+	defer stop()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if m.GetInt("server.port") == 0 {
+				b.Error("server.port is zero")
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// newTestManager 创建使用内存配置来源的管理器并启动，不执行内置校验器
+func newTestManager(t *testing.T, content string, opts ...ManagerOption) (*Manager, *MemorySource) {
+	t.Helper()
+	src := NewMemorySource(content)
+	m := NewManager(src, append([]ManagerOption{WithoutDefaultValidators()}, opts...)...)
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return m, src
+}
+
+func TestManagerStartLoadsSource(t *testing.T) {
+	m, _ := newTestManager(t, "app:\n  name: demo\nserver:\n  port: 20000\n")
+
+	if got := m.GetString("app.name"); got != "demo" {
+		t.Errorf("app.name = %q, want demo", got)
+	}
+	if got := m.GetInt("server.port"); got != 20000 {
+		t.Errorf("server.port = %d, want 20000", got)
+	}
+	status := m.CurrentVersion()
+	if status.Current != 1 || status.Remote != 1 {
+		t.Errorf("CurrentVersion() = %+v, want current=1 remote=1", status)
+	}
+	if status.Hash != contentHash("app:\n  name: demo\nserver:\n  port: 20000\n") {
+		t.Errorf("hash = %q, want hash of the loaded content", status.Hash)
+	}
+}
+
+func TestManagerStartRejectsInvalidContent(t *testing.T) {
+	m := NewManager(NewMemorySource("app: [unclosed"), WithoutDefaultValidators())
+	if err := m.Start(); err == nil {
+		t.Fatal("Start() error = nil, want parse error")
+	}
+	if m.IsSet("app") {
+		t.Error("app is set after a failed start")
+	}
+}
+
+func TestManagerPushNotifiesListeners(t *testing.T) {
+	m, src := newTestManager(t, "app:\n  name: v1\n")
+
+	var got []string
+	m.RegisterChangeListener(func(data map[string]interface{}) {
+		app, _ := data["app"].(map[string]interface{})
+		got = append(got, fmt.Sprint(app["name"]))
+		// 监听者拿到的是副本，修改不影响生效的配置
+		app["name"] = "mutated"
+	})
+
+	src.Set("app:\n  name: v2\n")
+
+	if len(got) != 1 || got[0] != "v2" {
+		t.Fatalf("listener calls = %v, want [v2]", got)
+	}
+	if name := m.GetString("app.name"); name != "v2" {
+		t.Errorf("app.name = %q, want v2", name)
+	}
+	if v := m.CurrentVersion().Current; v != 2 {
+		t.Errorf("current version = %d, want 2", v)
+	}
+}
+
+func TestManagerRejectedPushKeepsCurrent(t *testing.T) {
+	m, src := newTestManager(t, "app:\n  name: good\n")
+	m.RegisterValidator("name", func(_ context.Context, next, _ map[string]interface{}) error {
+		app, _ := next["app"].(map[string]interface{})
+		if app["name"] == "bad" {
+			return errors.New("bad name")
+		}
+		return nil
+	})
+
+	calls := 0
+	m.RegisterChangeListener(func(map[string]interface{}) { calls++ })
+	before := m.Snapshot()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "validator", content: "app:\n  name: bad\n"},
+		{name: "parse", content: "app: [unclosed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src.Set(tt.content)
+
+			if got := m.GetString("app.name"); got != "good" {
+				t.Errorf("app.name = %q, want good", got)
+			}
+			status := m.CurrentVersion()
+			if status.Current != before.Version() || status.Hash != before.Hash() {
+				t.Errorf("CurrentVersion() = %+v, want version %d hash %s", status, before.Version(), shortHash(before.Hash()))
+			}
+			if calls != 0 {
+				t.Errorf("listener called %d times for a rejected push", calls)
+			}
+		})
+	}
+
+	history := m.History()
+	if len(history) != 3 {
+		t.Fatalf("history length = %d, want 3", len(history))
+	}
+	for _, v := range history[:2] {
+		if v.Applied || v.Error == "" {
+			t.Errorf("v%d applied=%v error=%q, want rejected with error", v.Version, v.Applied, v.Error)
+		}
+	}
+}
+
+func TestManagersAreIsolated(t *testing.T) {
+	const tenants = 2
+	var wg sync.WaitGroup
+	managers := make([]*Manager, tenants)
+	for i := 0; i < tenants; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			src := NewMemorySource(fmt.Sprintf("app:\n  name: tenant-%d\n", i))
+			m := NewManager(src, WithoutDefaultValidators())
+			if err := m.Start(); err != nil {
+				t.Errorf("tenant %d Start() error = %v", i, err)
+				return
+			}
+			for j := 1; j <= 20; j++ {
+				src.Set(fmt.Sprintf("app:\n  name: tenant-%d\n  seq: %d\n", i, j))
+			}
+			managers[i] = m
+		}(i)
+	}
+	wg.Wait()
+
+	for i, m := range managers {
+		if m == nil {
+			continue
+		}
+		if got, want := m.GetString("app.name"), fmt.Sprintf("tenant-%d", i); got != want {
+			t.Errorf("tenant %d app.name = %q, want %q", i, got, want)
+		}
+		if got := m.GetInt("app.seq"); got != 20 {
+			t.Errorf("tenant %d app.seq = %d, want 20", i, got)
+		}
+		if got := m.CurrentVersion().Current; got != 21 {
+			t.Errorf("tenant %d current version = %d, want 21", i, got)
+		}
+	}
+	if defaultManager.IsSet("app.name") {
+		t.Errorf("default manager app.name = %v, want unset", defaultManager.Get("app.name"))
+	}
+	if got := defaultManager.CurrentVersion().Current; got != 0 {
+		t.Errorf("default manager current version = %d, want 0", got)
+	}
+}
+
+func TestSlowValidatorDoesNotBlockRollback(t *testing.T) {
+	m, src := newTestManager(t, "app:\n  name: v1\n")
+	src.Set("app:\n  name: v2\n")
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	m.RegisterValidator("slow", func(_ context.Context, next, _ map[string]interface{}) error {
+		if app, _ := next["app"].(map[string]interface{}); app["name"] == "v3" {
+			// 回滚后按新的当前配置重新校验，只阻塞第一次
+			once.Do(func() {
+				close(entered)
+				<-release
+			})
+		}
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		src.Set("app:\n  name: v3\n")
+	}()
+	<-entered
+
+	// 推送校验期间回滚不被阻塞
+	if _, err := m.Rollback(1); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if got := m.GetString("app.name"); got != "v1" {
+		t.Errorf("app.name after rollback = %q, want v1", got)
+	}
+
+	close(release)
+	<-done
+	if got := m.GetString("app.name"); got != "v3" {
+		t.Errorf("app.name after push = %q, want v3", got)
+	}
+}
+
+func TestListenersRunOutsideApplyLock(t *testing.T) {
+	m, src := newTestManager(t, "app:\n  name: v1\n")
+
+	var status VersionStatus
+	m.RegisterChangeListener(func(map[string]interface{}) {
+		// 回调中可以读取版本状态、发起回滚而不死锁
+		locked := m.applyMu.TryLock()
+		if locked {
+			m.applyMu.Unlock()
+		}
+		if !locked {
+			t.Error("listener called while holding applyMu")
+		}
+		status = m.CurrentVersion()
+	})
+	src.Set("app:\n  name: v2\n")
+	if status.Current != 2 {
+		t.Errorf("listener saw version %d, want 2", status.Current)
+	}
+}
+
+func TestScalarReadsDoNotCopy(t *testing.T) {
+	m, _ := newTestManager(t, "app:\n  name: demo\n  tags: [a, b]\nserver:\n  port: 20000\n")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = m.GetString("app.name")
+		_ = m.GetInt("server.port")
+		_ = m.Get("app.tags")
+	})
+	if allocs != 0 {
+		t.Errorf("scalar reads allocate %v times, want 0", allocs)
+	}
+
+	// GetStringMap、GetAll、Bind 返回副本
+	m.GetStringMap("app")["name"] = "mutated"
+	m.GetAll()["server"].(map[string]interface{})["port"] = 1
+	var app struct {
+		Tags []interface{} `yaml:"tags"`
+	}
+	if err := m.Bind("app", &app); err != nil {
+		t.Fatal(err)
+	}
+	app.Tags[0] = "mutated"
+	if m.GetString("app.name") != "demo" || m.GetInt("server.port") != 20000 || m.GetStringSlice("app.tags")[0] != "a" {
+		t.Errorf("config changed through a returned copy: %v", m.GetAll())
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 配置热更新指标，通过管理端 /metrics 暴露
+var (
+	// reloadTotal 配置更新次数，result: applied, rejected, superseded, fallback
+	reloadTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "helloworld",
+		Subsystem: "config",
+		Name:      "reload_total",
+		Help:      "App config reloads by result.",
+	}, []string{"result"})
+
+	// validationFailures 各校验器拒绝配置的次数
+	validationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "helloworld",
+		Subsystem: "config",
+		Name:      "validation_failures_total",
+		Help:      "App config validation failures by validator.",
+	}, []string{"validator"})
+
+	// configInfo 当前生效的配置版本和内容 hash，值恒为 1，配置更新时替换
+	configInfo = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "helloworld",
+		Subsystem: "config",
+		Name:      "info",
+		Help:      "Currently applied app config version and content hash, value is always 1.",
+	}, []string{"version", "hash"})
+)
+
+// 配置更新结果
+const (
+	reloadApplied    = "applied"    // 校验通过并生效
+	reloadRejected   = "rejected"   // 校验失败，保留当前配置
+	reloadSuperseded = "superseded" // 灰度等待期间被更新的推送替代
+	reloadFallback   = "fallback"   // 启动时校验失败，使用历史中最近一次生效的版本
+)
+
+// setConfigInfo 更新当前生效配置的指标，只保留最新一组标签
+func setConfigInfo(v ConfigVersion) {
+	configInfo.Reset()
+	configInfo.WithLabelValues(strconv.FormatInt(v.Version, 10), shortHash(v.Hash)).Set(1)
+}
@@ -5,10 +5,10 @@ import (
 	"net/url"
 	"time"
 
-	"github.com/dubbogo/gost/log/logger"
+	"helloworld/pkg/log"
+
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	gormlogger "gorm.io/gorm/logger"
 )
 
 // MySQLConfig MySQL 配置结构体
@@ -26,6 +26,12 @@ type MySQLConfig struct {
 	MaxIdleConns    int           `json:"max_idle_conns" yaml:"max_idle_conns"`
 	MaxOpenConns    int           `json:"max_open_conns" yaml:"max_open_conns"`
 	ConnMaxLifetime time.Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+
+	// 日志配置，级别通过 log.levels.gorm 或 log_level 控制
+	LogLevel                  string        `json:"log_level" yaml:"log_level"`                             // silent, error, warn, info
+	SlowThreshold             time.Duration `json:"slow_threshold" yaml:"slow_threshold"`                   // 慢查询阈值，默认 200ms
+	IgnoreRecordNotFoundError bool          `json:"ignore_record_not_found" yaml:"ignore_record_not_found"` // 不记录 ErrRecordNotFound，默认 true
+	RedactSQL                 bool          `json:"redact_sql" yaml:"redact_sql"`                           // 日志中的 SQL 参数替换为 ?，默认 true
 }
 
 // CreateDB 创建 GORM 数据库连接
@@ -42,8 +48,12 @@ func (mc *MySQLConfig) CreateDB() (*gorm.DB, error) {
 		SkipDefaultTransaction: true,
 		// 预编译SQL
 		PrepareStmt: true,
-		// 日志配置
-		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+		// 日志写入 zap，输出级别由 "gorm" logger 控制
+		Logger: log.NewGormLogger(log.GormOptions{
+			SlowThreshold:             mc.SlowThreshold,
+			IgnoreRecordNotFoundError: mc.IgnoreRecordNotFoundError,
+			RedactParams:              mc.RedactSQL,
+		}),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -125,78 +135,28 @@ func (mc *MySQLConfig) DSN() string {
 	return dsn
 }
 
-// parseDurationValue 解析duration值（支持多种类型）
-func parseDurationValue(value interface{}) time.Duration {
-	switch v := value.(type) {
-	case string:
-		return parseDuration(v)
-	case int:
-		return time.Duration(v) * time.Second
-	case int64:
-		return time.Duration(v) * time.Second
-	case float64:
-		return time.Duration(v) * time.Second
-	case float32:
-		return time.Duration(v) * time.Second
-	default:
-		logger.Errorf("Unsupported duration type %T for value %v", value, value)
-		return 0
-	}
-}
-
-// GetMySQLConfigFromDubbo 从 dubbo-go 配置中心获取 MySQL 配置
-func GetMySQLConfigFromDubbo() (*MySQLConfig, error) {
+// GetMySQLConfig 获取 MySQL 配置
+func (m *Manager) GetMySQLConfig() (*MySQLConfig, error) {
 	// 从配置管理器获取配置
-	mysqlMap := GetStringMap("mysql")
+	mysqlMap := m.GetStringMap("mysql")
 	if mysqlMap == nil {
 		return nil, fmt.Errorf("mysql config not found")
 	}
+	return ParseMySQLConfig(mysqlMap)
+}
 
-	config := &MySQLConfig{}
-
-	// 解析各个字段
-	if v, ok := mysqlMap["host"].(string); ok {
-		config.Host = v
-	}
-	if v, ok := mysqlMap["port"].(int); ok {
-		config.Port = v
-	}
-	if v, ok := mysqlMap["username"].(string); ok {
-		config.Username = v
-	}
-	if v, ok := mysqlMap["password"].(string); ok {
-		config.Password = v
-	}
-	if v, ok := mysqlMap["database"].(string); ok {
-		config.Database = v
-	}
-	if v, ok := mysqlMap["charset"].(string); ok {
-		config.Charset = v
-	}
-	if v, ok := mysqlMap["location"].(string); ok {
-		config.Location = v
-	}
-
-	// 解析超时配置
-	if v, ok := mysqlMap["conn_timeout"]; ok {
-		config.ConnTimeout = parseDurationValue(v)
-	}
-	if v, ok := mysqlMap["read_timeout"]; ok {
-		config.ReadTimeout = parseDurationValue(v)
-	}
-	if v, ok := mysqlMap["write_timeout"]; ok {
-		config.WriteTimeout = parseDurationValue(v)
-	}
-	if v, ok := mysqlMap["conn_max_lifetime"]; ok {
-		config.ConnMaxLifetime = parseDurationValue(v)
+// ParseMySQLConfig 从配置 map 中解析 MySQL 配置
+func ParseMySQLConfig(mysqlMap map[string]interface{}) (*MySQLConfig, error) {
+	config := &MySQLConfig{
+		LogLevel:                  "warn",
+		SlowThreshold:             200 * time.Millisecond,
+		IgnoreRecordNotFoundError: true,
+		RedactSQL:                 true,
 	}
 
-	// 解析连接池配置
-	if v, ok := mysqlMap["max_idle_conns"].(int); ok {
-		config.MaxIdleConns = v
-	}
-	if v, ok := mysqlMap["max_open_conns"].(int); ok {
-		config.MaxOpenConns = v
+	// 解析各个字段，端口、超时等支持字符串和数字
+	if err := bindValue("mysql", mysqlMap, config); err != nil {
+		return nil, fmt.Errorf("invalid mysql config: %w", err)
 	}
 
 	// 设置默认值
@@ -219,3 +179,8 @@ func GetMySQLConfigFromDubbo() (*MySQLConfig, error) {
 	logger.Infof("Loaded MySQL config: %+v", config)
 	return config, nil
 }
+
+// GetMySQLConfigFromDubbo 从 dubbo-go 配置中心获取 MySQL 配置
+func GetMySQLConfigFromDubbo() (*MySQLConfig, error) {
+	return defaultManager.GetMySQLConfig()
+}
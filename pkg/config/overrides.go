@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"path"
+	"strings"
+)
+
+// overridesKey 应用配置中的 overrides 段，合并后从生效配置中移除
+const overridesKey = "overrides"
+
+// InstanceInfo 当前实例的标签，用于匹配 overrides 的选择器
+type InstanceInfo struct {
+	ID       string            `json:"id"`       // 实例 ID，用于按比例灰度，默认主机名
+	Hostname string            `json:"hostname"` // 主机名（Kubernetes 中为 Pod 名）
+	IPs      []string          `json:"ips"`      // 实例 IP
+	Zone     string            `json:"zone"`     // 可用区
+	Labels   map[string]string `json:"labels"`   // 其他标签
+}
+
+// DetectInstance 读取当前实例的标签：
+// INSTANCE_ID、INSTANCE_ZONE、INSTANCE_LABELS（k1=v1,k2=v2）、POD_IP 环境变量，主机名和网卡地址
+func DetectInstance() InstanceInfo {
+	info := InstanceInfo{
+		ID:     os.Getenv("INSTANCE_ID"),
+		Zone:   os.Getenv("INSTANCE_ZONE"),
+		Labels: make(map[string]string),
+	}
+	info.Hostname, _ = os.Hostname()
+	if info.ID == "" {
+		info.ID = info.Hostname
+	}
+	for _, kv := range strings.Split(os.Getenv("INSTANCE_LABELS"), ",") {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			info.Labels[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	if ip := os.Getenv("POD_IP"); ip != "" {
+		info.IPs = append(info.IPs, ip)
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() {
+				info.IPs = append(info.IPs, ipNet.IP.String())
+			}
+		}
+	}
+	return info
+}
+
+// Instance 匹配 overrides 使用的实例标签
+func (m *Manager) Instance() InstanceInfo {
+	return m.instance
+}
+
+// Instance 默认管理器匹配 overrides 使用的实例标签
+func Instance() InstanceInfo {
+	return defaultManager.Instance()
+}
+
+// Override overrides 中的一项：实例满足 match 中的所有选择器时，把 config 合并到配置中
+type Override struct {
+	ID     string                 `yaml:"id"`
+	Match  OverrideMatch          `yaml:"match"`
+	Config map[string]interface{} `yaml:"config"`
+}
+
+// OverrideMatch 选择器，设置了的选择器都需要满足；列表中任意一项匹配即可
+type OverrideMatch struct {
+	Hostnames []string          `yaml:"hostnames"` // 主机名，支持 * ? 通配符，如 go-server-7d9f*
+	CIDRs     []string          `yaml:"cidrs"`     // 实例 IP 所在网段，如 10.0.1.0/24
+	Zones     []string          `yaml:"zones"`     // 可用区
+	Labels    map[string]string `yaml:"labels"`    // 标签，所有标签都需要相等
+	Percent   *float64          `yaml:"percent"`   // 按实例 ID 哈希选择的比例(0~100)，增大比例时已命中的实例保持命中
+}
+
+// parseOverrides 解析 overrides 段，检查 id 唯一、选择器非空且格式正确
+func parseOverrides(raw interface{}) ([]Override, error) {
+	var list []Override
+	if err := bindValue(overridesKey, raw, &list); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(list))
+	var errs ValidationErrors
+	for i, o := range list {
+		p := fmt.Sprintf("%s[%d]", overridesKey, i)
+		switch {
+		case o.ID == "":
+			errs = append(errs, ValidationError{Path: p + ".id", Message: "is required"})
+		case seen[o.ID]:
+			errs = append(errs, ValidationError{Path: p + ".id", Message: fmt.Sprintf("duplicate id %q", o.ID)})
+		}
+		seen[o.ID] = true
+		if err := o.Match.check(); err != nil {
+			errs = append(errs, ValidationError{Path: p + ".match", Message: err.Error()})
+		}
+		if _, ok := o.Config[overridesKey]; ok {
+			errs = append(errs, ValidationError{Path: p + ".config", Message: "must not contain overrides"})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return list, nil
+}
+
+// check 检查选择器格式
+func (m OverrideMatch) check() error {
+	if len(m.Hostnames) == 0 && len(m.CIDRs) == 0 && len(m.Zones) == 0 && len(m.Labels) == 0 && m.Percent == nil {
+		return fmt.Errorf("at least one selector is required")
+	}
+	for _, pattern := range m.Hostnames {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid hostname pattern %q", pattern)
+		}
+	}
+	for _, cidr := range m.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid cidr %q", cidr)
+		}
+	}
+	if m.Percent != nil && (*m.Percent < 0 || *m.Percent > 100) {
+		return fmt.Errorf("percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// Matches 判断实例是否满足选择器，id 参与按比例选择的哈希，使不同 override 选中的实例相互独立
+func (m OverrideMatch) Matches(id string, inst InstanceInfo) bool {
+	if len(m.Hostnames) > 0 && !matchAny(m.Hostnames, func(p string) bool {
+		ok, _ := path.Match(p, inst.Hostname)
+		return ok
+	}) {
+		return false
+	}
+	if len(m.CIDRs) > 0 && !matchAny(m.CIDRs, func(cidr string) bool {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return false
+		}
+		for _, ip := range inst.IPs {
+			if parsed := net.ParseIP(ip); parsed != nil && ipNet.Contains(parsed) {
+				return true
+			}
+		}
+		return false
+	}) {
+		return false
+	}
+	if len(m.Zones) > 0 && !matchAny(m.Zones, func(z string) bool { return z == inst.Zone }) {
+		return false
+	}
+	for k, v := range m.Labels {
+		if inst.Labels[k] != v {
+			return false
+		}
+	}
+	if m.Percent != nil && instanceBucket(id, inst.ID) >= *m.Percent {
+		return false
+	}
+	return true
+}
+
+// matchAny 列表中任意一项满足
+func matchAny(items []string, fn func(string) bool) bool {
+	for _, item := range items {
+		if fn(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// instanceBucket 实例在某个 override 下的哈希桶，范围 [0, 100)，精度 0.01
+func instanceBucket(overrideID, instanceID string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(overrideID + "/" + instanceID))
+	return float64(h.Sum32()%10000) / 100
+}
+
+// applyOverrides 按顺序合并命中的 overrides，返回不含 overrides 段的配置和命中的 id
+func applyOverrides(data map[string]interface{}, inst InstanceInfo) (map[string]interface{}, []string, error) {
+	raw, ok := data[overridesKey]
+	if !ok {
+		return data, nil, nil
+	}
+	list, err := parseOverrides(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(data, overridesKey)
+
+	var matched []string
+	for _, o := range list {
+		if !o.Match.Matches(o.ID, inst) {
+			continue
+		}
+		mergeMaps(data, deepCopyMap(o.Config))
+		matched = append(matched, o.ID)
+	}
+	return data, matched, nil
+}
+
+// mergeMaps 把 src 深度合并到 dst：对象按 key 递归合并，列表和标量整体替换
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]interface{}); ok {
+			if dm, ok := dst[k].(map[string]interface{}); ok {
+				mergeMaps(dm, sm)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
@@ -0,0 +1,274 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testInstance 测试用的实例标签
+var testInstance = InstanceInfo{
+	ID:       "go-server-7d9f-abc12",
+	Hostname: "go-server-7d9f-abc12",
+	IPs:      []string{"10.0.1.15", "fd00::15"},
+	Zone:     "cn-hz-a",
+	Labels:   map[string]string{"track": "canary", "tier": "gold"},
+}
+
+// percent 返回 percent 选择器的值
+func percent(p float64) *float64 {
+	return &p
+}
+
+func TestOverrideMatchMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		match OverrideMatch
+		want  bool
+	}{
+		{name: "hostname exact", match: OverrideMatch{Hostnames: []string{"go-server-7d9f-abc12"}}, want: true},
+		{name: "hostname glob", match: OverrideMatch{Hostnames: []string{"go-server-7d9f*"}}, want: true},
+		{name: "hostname single char", match: OverrideMatch{Hostnames: []string{"go-server-7d9f-abc1?"}}, want: true},
+		{name: "hostname any of", match: OverrideMatch{Hostnames: []string{"other-*", "go-server-*"}}, want: true},
+		{name: "hostname miss", match: OverrideMatch{Hostnames: []string{"go-client-*"}}, want: false},
+		{name: "cidr v4", match: OverrideMatch{CIDRs: []string{"10.0.1.0/24"}}, want: true},
+		{name: "cidr v6", match: OverrideMatch{CIDRs: []string{"fd00::/64"}}, want: true},
+		{name: "cidr miss", match: OverrideMatch{CIDRs: []string{"10.0.2.0/24"}}, want: false},
+		{name: "zone", match: OverrideMatch{Zones: []string{"cn-hz-b", "cn-hz-a"}}, want: true},
+		{name: "zone miss", match: OverrideMatch{Zones: []string{"cn-hz-b"}}, want: false},
+		{name: "labels all equal", match: OverrideMatch{Labels: map[string]string{"track": "canary", "tier": "gold"}}, want: true},
+		{name: "labels one differs", match: OverrideMatch{Labels: map[string]string{"track": "canary", "tier": "silver"}}, want: false},
+		{name: "labels missing", match: OverrideMatch{Labels: map[string]string{"region": "hz"}}, want: false},
+		{name: "percent 100", match: OverrideMatch{Percent: percent(100)}, want: true},
+		{name: "percent 0", match: OverrideMatch{Percent: percent(0)}, want: false},
+		{
+			name: "all selectors",
+			match: OverrideMatch{
+				Hostnames: []string{"go-server-*"},
+				CIDRs:     []string{"10.0.0.0/16"},
+				Zones:     []string{"cn-hz-a"},
+				Labels:    map[string]string{"track": "canary"},
+				Percent:   percent(100),
+			},
+			want: true,
+		},
+		{
+			name: "one selector fails",
+			match: OverrideMatch{
+				Hostnames: []string{"go-server-*"},
+				Zones:     []string{"cn-hz-b"},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.match.Matches("canary", testInstance); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverridePercentMonotonic(t *testing.T) {
+	const instances = 1000
+	prev := make(map[string]bool)
+	for _, p := range []float64{1, 5, 10, 25, 50, 75, 100} {
+		selected := 0
+		for i := 0; i < instances; i++ {
+			inst := InstanceInfo{ID: fmt.Sprintf("go-server-%d", i)}
+			hit := OverrideMatch{Percent: percent(p)}.Matches("rollout", inst)
+			if prev[inst.ID] && !hit {
+				t.Fatalf("instance %s matched at a lower percent but not at %v", inst.ID, p)
+			}
+			prev[inst.ID] = hit
+			if hit {
+				selected++
+			}
+		}
+		// 哈希分布大致均匀
+		if want := p * instances / 100; float64(selected) < want-50 || float64(selected) > want+50 {
+			t.Errorf("percent %v selected %d of %d instances, want about %v", p, selected, instances, want)
+		}
+	}
+}
+
+func TestInstanceBucket(t *testing.T) {
+	b := instanceBucket("rollout", "go-server-1")
+	if b < 0 || b >= 100 {
+		t.Errorf("bucket = %v, want [0, 100)", b)
+	}
+	if again := instanceBucket("rollout", "go-server-1"); again != b {
+		t.Errorf("bucket is not stable: %v != %v", again, b)
+	}
+	// 不同 override 独立选择实例
+	differs := false
+	for i := 0; i < 20 && !differs; i++ {
+		id := fmt.Sprintf("go-server-%d", i)
+		differs = instanceBucket("a", id) != instanceBucket("b", id)
+	}
+	if !differs {
+		t.Error("buckets of different overrides are identical")
+	}
+}
+
+func TestParseOverridesValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string // 错误信息包含的内容，为空表示合法
+	}{
+		{
+			name: "valid",
+			raw:  "- id: a\n  match: {zones: [z1]}\n  config: {x: 1}\n- id: b\n  match: {percent: 10}\n",
+		},
+		{name: "missing id", raw: "- match: {zones: [z1]}\n", want: "overrides[0].id: is required"},
+		{name: "duplicate id", raw: "- id: a\n  match: {zones: [z1]}\n- id: a\n  match: {zones: [z2]}\n", want: `overrides[1].id: duplicate id "a"`},
+		{name: "empty match", raw: "- id: a\n  config: {x: 1}\n", want: "overrides[0].match: at least one selector is required"},
+		{name: "bad hostname", raw: "- id: a\n  match: {hostnames: ['[']}\n", want: `invalid hostname pattern "["`},
+		{name: "bad cidr", raw: "- id: a\n  match: {cidrs: [10.0.0.0/33]}\n", want: `invalid cidr "10.0.0.0/33"`},
+		{name: "percent over 100", raw: "- id: a\n  match: {percent: 101}\n", want: "percent must be between 0 and 100"},
+		{name: "negative percent", raw: "- id: a\n  match: {percent: -1}\n", want: "percent must be between 0 and 100"},
+		{name: "nested overrides", raw: "- id: a\n  match: {zones: [z1]}\n  config: {overrides: []}\n", want: "overrides[0].config: must not contain overrides"},
+		{name: "not a list", raw: "id: a\n", want: "overrides"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := decodeConfig(FormatYAML, []byte("overrides:\n"+indent(tt.raw)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = parseOverrides(data[overridesKey])
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("parseOverrides() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseOverrides() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// indent 缩进 YAML 片段
+func indent(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = "  " + l
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestApplyOverrides(t *testing.T) {
+	content := `
+log:
+  level: info
+  outputs:
+    - type: stdout
+    - type: file
+redis:
+  host: r1
+  port: 6379
+overrides:
+  - id: canary
+    match:
+      labels: {track: canary}
+    config:
+      log:
+        level: debug
+        outputs:
+          - type: stderr
+      redis:
+        host: r2
+  - id: other-zone
+    match:
+      zones: [cn-sh-a]
+    config:
+      redis:
+        host: r3
+  - id: gold
+    match:
+      labels: {tier: gold}
+    config:
+      redis:
+        port: 6380
+`
+	data, err := decodeConfig(FormatYAML, []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, matched, err := applyOverrides(data, testInstance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(matched, []string{"canary", "gold"}) {
+		t.Errorf("matched = %v, want [canary gold]", matched)
+	}
+	want := map[string]interface{}{
+		"log": map[string]interface{}{
+			"level": "debug",
+			// 列表整体替换，不按下标合并
+			"outputs": []interface{}{map[string]interface{}{"type": "stderr"}},
+		},
+		"redis": map[string]interface{}{"host": "r2", "port": 6380},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("config = %#v\nwant %#v", got, want)
+	}
+}
+
+func TestApplyOverridesWithoutSection(t *testing.T) {
+	data := map[string]interface{}{"a": 1}
+	got, matched, err := applyOverrides(data, testInstance)
+	if err != nil || matched != nil || !reflect.DeepEqual(got, data) {
+		t.Errorf("applyOverrides() = %v, %v, %v, want data unchanged", got, matched, err)
+	}
+}
+
+func TestMergeMaps(t *testing.T) {
+	dst := map[string]interface{}{
+		"a":    map[string]interface{}{"x": 1, "y": 2},
+		"list": []interface{}{1, 2, 3},
+		"s":    "old",
+		"m":    map[string]interface{}{"k": 1},
+	}
+	mergeMaps(dst, map[string]interface{}{
+		"a":    map[string]interface{}{"y": 20, "z": 30},
+		"list": []interface{}{9},
+		"s":    map[string]interface{}{"now": "map"},
+		"m":    "scalar",
+		"new":  true,
+	})
+	want := map[string]interface{}{
+		"a":    map[string]interface{}{"x": 1, "y": 20, "z": 30},
+		"list": []interface{}{9},
+		"s":    map[string]interface{}{"now": "map"},
+		"m":    "scalar",
+		"new":  true,
+	}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("merged = %#v\nwant %#v", dst, want)
+	}
+}
+
+func TestManagerAppliesOverridesForInstance(t *testing.T) {
+	content := "app:\n  greeting: hello\noverrides:\n  - id: canary\n    match: {labels: {track: canary}}\n    config:\n      app: {greeting: hi}\n"
+	canary, _ := newTestManager(t, content, WithInstance(testInstance))
+	stable, _ := newTestManager(t, content, WithInstance(InstanceInfo{ID: "stable-1", Hostname: "stable-1"}))
+
+	if got := canary.GetString("app.greeting"); got != "hi" {
+		t.Errorf("canary app.greeting = %q, want hi", got)
+	}
+	if got := canary.CurrentVersion().Overrides; !reflect.DeepEqual(got, []string{"canary"}) {
+		t.Errorf("canary overrides = %v, want [canary]", got)
+	}
+	if got := stable.GetString("app.greeting"); got != "hello" {
+		t.Errorf("stable app.greeting = %q, want hello", got)
+	}
+	if canary.IsSet(overridesKey) || stable.IsSet(overridesKey) {
+		t.Error("overrides section is visible in the effective config")
+	}
+}
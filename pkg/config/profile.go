@@ -0,0 +1,240 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Profile 运行环境，决定默认的 Nacos 命名空间、分组、data ID、注册中心地址和日志级别
+// 命令行参数和环境变量中显式设置的值优先于 profile 的默认值
+type Profile struct {
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace" yaml:"namespace"`             // Nacos 命名空间
+	Group         string `json:"group" yaml:"group"`                     // Nacos 分组
+	DataIDPattern string `json:"data_id_pattern" yaml:"data_id_pattern"` // data ID 模板，{app} 替换为应用名，{profile} 替换为 profile 名
+	RegistryAddr  string `json:"registry_addr" yaml:"registry_addr"`     // Nacos 地址（注册中心和配置中心）
+	LogLevel      string `json:"log_level" yaml:"log_level"`             // 应用配置中没有 log.level 时的日志级别
+}
+
+// DataID 按模板生成 data ID
+func (p Profile) DataID(appName string) string {
+	r := strings.NewReplacer("{app}", appName, "{profile}", p.Name)
+	return r.Replace(p.DataIDPattern)
+}
+
+// builtinProfiles 内置的环境，地址只是默认值，实际部署通过 LoadProfiles 覆盖
+// data ID 带环境后缀（如 go-server-prod），即使命名空间被覆盖也不会读到其他环境的配置
+var builtinProfiles = map[string]Profile{
+	"dev": {
+		Name:          "dev",
+		Namespace:     "dev",
+		Group:         "DEFAULT_GROUP",
+		DataIDPattern: "{app}-{profile}",
+		RegistryAddr:  "127.0.0.1:8848",
+		LogLevel:      "debug",
+	},
+	"test": {
+		Name:          "test",
+		Namespace:     "test",
+		Group:         "DEFAULT_GROUP",
+		DataIDPattern: "{app}-{profile}",
+		RegistryAddr:  "nacos-test:8848",
+		LogLevel:      "debug",
+	},
+	"staging": {
+		Name:          "staging",
+		Namespace:     "staging",
+		Group:         "DEFAULT_GROUP",
+		DataIDPattern: "{app}-{profile}",
+		RegistryAddr:  "nacos-staging:8848",
+		LogLevel:      "info",
+	},
+	"prod": {
+		Name:          "prod",
+		Namespace:     "prod",
+		Group:         "DEFAULT_GROUP",
+		DataIDPattern: "{app}-{profile}",
+		RegistryAddr:  "nacos-prod:8848",
+		LogLevel:      "info",
+	},
+}
+
+// profiles 生效的环境表：内置环境合并 LoadProfiles 加载的文件
+var profiles = builtinProfiles
+
+// LoadProfiles 从文件加载环境表，格式按扩展名判断，顶层 key 为 profile 名：
+//
+//	prod:
+//	  namespace: 5f1c...        # 未设置的字段沿用内置值
+//	  registry_addr: 10.0.3.11:8848,10.0.3.12:8848
+//	canary:                     # 新增的 profile 必须设置 namespace 和 registry_addr
+//	  namespace: canary
+//	  registry_addr: 10.0.4.11:8848
+//
+// 需要在 LookupProfile 之前调用，path 为空时只使用内置环境
+func LoadProfiles(path string) error {
+	if path == "" {
+		profiles = builtinProfiles
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read profiles file: %w", err)
+	}
+	data, err := decodeConfig(FormatFromDataID(path), content)
+	if err != nil {
+		return fmt.Errorf("profiles file %s: %w", path, err)
+	}
+	loaded := make(map[string]Profile)
+	if err := bindValue("", data, &loaded); err != nil {
+		return fmt.Errorf("profiles file %s: %w", path, err)
+	}
+	merged, err := mergeProfiles(builtinProfiles, loaded)
+	if err != nil {
+		return fmt.Errorf("profiles file %s: %w", path, err)
+	}
+	profiles = merged
+	return nil
+}
+
+// mergeProfiles 将 overrides 中非空的字段覆盖到 base 上，返回新的环境表
+func mergeProfiles(base, overrides map[string]Profile) (map[string]Profile, error) {
+	merged := make(map[string]Profile, len(base)+len(overrides))
+	for name, p := range base {
+		merged[name] = p
+	}
+	for name, o := range overrides {
+		name = strings.ToLower(name)
+		p, ok := merged[name]
+		if !ok {
+			if o.Namespace == "" || o.RegistryAddr == "" {
+				return nil, fmt.Errorf("profile %s: namespace and registry_addr are required for a new profile", name)
+			}
+			p = Profile{Group: defaultNacosConfig.Group, DataIDPattern: "{app}-{profile}", LogLevel: "info"}
+		}
+		p.Name = name
+		override(&p.Namespace, o.Namespace)
+		override(&p.Group, o.Group)
+		override(&p.DataIDPattern, o.DataIDPattern)
+		override(&p.RegistryAddr, o.RegistryAddr)
+		override(&p.LogLevel, o.LogLevel)
+		merged[name] = p
+	}
+	return merged, nil
+}
+
+// override src 非空时覆盖 dst
+func override(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+// noProfile 未指定 profile 时的默认值，与引入 profile 之前的行为一致
+var noProfile = Profile{
+	Namespace:     defaultNacosConfig.Namespace,
+	Group:         defaultNacosConfig.Group,
+	DataIDPattern: "{app}",
+	RegistryAddr:  defaultNacosConfig.Address,
+	LogLevel:      "info",
+}
+
+// LookupProfile 查找 profile，name 为空时返回不绑定环境的默认值
+func LookupProfile(name string) (Profile, error) {
+	if name == "" {
+		return noProfile, nil
+	}
+	p, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q, expected one of %s", name, strings.Join(ProfileNames(), ", "))
+	}
+	return p, nil
+}
+
+// ProfileNames 生效的 profile 名称
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkProfileTarget 防止跨环境误用：指定 profile 后，命名空间和 Nacos 地址不能是另一个 profile 的
+// 例如 -profile dev 配合 NACOS_NAMESPACE=prod 或 NACOS_ADDR=nacos-prod:8848 会读取并修改生产环境的配置和注册中心
+// 多个 profile 共用同一个 Nacos 集群时，该地址不作为其他环境的地址
+func checkProfileTarget(p Profile, namespace, addr string) error {
+	if p.Name == "" {
+		return nil
+	}
+	names := ProfileNames()
+	for _, name := range names {
+		other := profiles[name]
+		if other.Name != p.Name && namespace != p.Namespace && other.Namespace == namespace {
+			return fmt.Errorf("profile %s must not use namespace %q of profile %s", p.Name, namespace, other.Name)
+		}
+	}
+	own := splitAddrs(p.RegistryAddr)
+	for a := range splitAddrs(addr) {
+		if own[a] {
+			continue
+		}
+		for _, name := range names {
+			if other := profiles[name]; other.Name != p.Name && splitAddrs(other.RegistryAddr)[a] {
+				return fmt.Errorf("profile %s must not use nacos address %q of profile %s", p.Name, a, other.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// splitAddrs 拆分逗号分隔的 Nacos 地址，忽略大小写和 http(s):// 前缀
+func splitAddrs(addr string) map[string]bool {
+	set := make(map[string]bool)
+	for _, a := range strings.Split(addr, ",") {
+		a = strings.ToLower(strings.TrimSpace(a))
+		a = strings.TrimPrefix(strings.TrimPrefix(a, "http://"), "https://")
+		if a = strings.TrimSuffix(a, "/"); a != "" {
+			set[a] = true
+		}
+	}
+	return set
+}
+
+// activeProfile 当前进程的 profile，由 SetProfile 设置
+var activeProfile atomic.Pointer[Profile]
+
+// SetProfile 设置当前 profile，之后的配置推送会校验配置中的 profile 字段
+func SetProfile(p Profile) {
+	activeProfile.Store(&p)
+}
+
+// ActiveProfile 当前 profile，未设置时 Name 为空
+func ActiveProfile() Profile {
+	if p := activeProfile.Load(); p != nil {
+		return *p
+	}
+	return noProfile
+}
+
+// validateProfile 应用配置中声明了 profile 时必须与当前进程一致，防止把其他环境的配置发布到当前命名空间
+func validateProfile(_ context.Context, next, _ map[string]interface{}) error {
+	return validateProfileOf(ActiveProfile(), next)
+}
+
+// validateProfileOf 校验配置中的 profile 字段，p 未绑定环境或配置未声明时不校验
+func validateProfileOf(p Profile, data map[string]interface{}) error {
+	declared, ok := data["profile"]
+	if !ok || p.Name == "" {
+		return nil
+	}
+	if name, _ := toString(declared); !strings.EqualFold(name, p.Name) {
+		return fmt.Errorf("config is for profile %v, but this instance runs with profile %s", declared, p.Name)
+	}
+	return nil
+}
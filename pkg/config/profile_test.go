@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	t.Cleanup(func() { _ = LoadProfiles("") })
+
+	tests := []struct {
+		name    string
+		content string
+		want    string // 错误信息，为空表示合法
+	}{
+		{
+			name: "override and add",
+			content: `
+prod:
+  namespace: 5f1c-prod
+  registry_addr: 10.0.3.11:8848,10.0.3.12:8848
+Canary:
+  namespace: canary
+  registry_addr: 10.0.4.11:8848
+`,
+		},
+		{name: "new profile without address", content: "canary:\n  namespace: canary\n", want: "namespace and registry_addr are required"},
+		{name: "wrong type", content: "prod:\n  namespace: [a]\n", want: "prod.namespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profiles.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := LoadProfiles(path)
+			if tt.want != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("LoadProfiles() error = %v, want %q", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadProfiles() error = %v", err)
+			}
+
+			prod, _ := LookupProfile("prod")
+			want := Profile{Name: "prod", Namespace: "5f1c-prod", Group: "DEFAULT_GROUP", DataIDPattern: "{app}-{profile}",
+				RegistryAddr: "10.0.3.11:8848,10.0.3.12:8848", LogLevel: "info"}
+			if prod != want {
+				t.Errorf("prod = %+v, want %+v", prod, want)
+			}
+			canary, err := LookupProfile("canary")
+			if err != nil || canary.DataID("go-server") != "go-server-canary" || canary.Group != "DEFAULT_GROUP" {
+				t.Errorf("canary = %+v, %v", canary, err)
+			}
+			// 文件中的地址同样用于跨环境检查，内置的示例地址不再属于 prod
+			if err := checkProfileTarget(canary, "canary", "10.0.3.12:8848"); err == nil {
+				t.Error("canary using the prod address was accepted")
+			}
+			if err := checkProfileTarget(canary, "canary", "nacos-prod:8848"); err != nil {
+				t.Errorf("checkProfileTarget() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+// RateLimitRule 令牌桶限流规则
+type RateLimitRule struct {
+	Rate  float64 `json:"rate" yaml:"rate"`   // 每秒生成令牌数
+	Burst int     `json:"burst" yaml:"burst"` // 桶容量（允许的突发请求数）
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	Enabled bool                     `json:"enabled" yaml:"enabled"`
+	Prefix  string                   `json:"prefix" yaml:"prefix"`   // Redis key 前缀
+	Methods map[string]RateLimitRule `json:"methods" yaml:"methods"` // 方法级限流，key 为 "方法名" 或 "接口名.方法名"
+	Callers map[string]RateLimitRule `json:"callers" yaml:"callers"` // 调用方级限流（按方法分别计数），"default" 为默认规则
+}
+
+// GetRateLimitConfig 获取限流配置
+func (m *Manager) GetRateLimitConfig() *RateLimitConfig {
+	config := &RateLimitConfig{
+		Prefix:  "helloworld:ratelimit:",
+		Methods: make(map[string]RateLimitRule),
+		Callers: make(map[string]RateLimitRule),
+	}
+
+	rlMap := m.GetStringMap("ratelimit")
+	if rlMap == nil {
+		return config
+	}
+
+	if err := bindValue("ratelimit", rlMap, config); err != nil {
+		logger.Warnf("Invalid ratelimit config, skipping invalid rules: %v", err)
+	}
+	normalizeRateLimitRules(config.Methods)
+	normalizeRateLimitRules(config.Callers)
+
+	return config
+}
+
+// normalizeRateLimitRules 删除 rate 未配置的规则，burst 未配置时取 rate
+func normalizeRateLimitRules(rules map[string]RateLimitRule) {
+	for name, rule := range rules {
+		if rule.Rate <= 0 {
+			delete(rules, name)
+			continue
+		}
+		if rule.Burst <= 0 {
+			rule.Burst = int(rule.Rate)
+			if rule.Burst < 1 {
+				rule.Burst = 1
+			}
+			rules[name] = rule
+		}
+	}
+}
+
+// GetRateLimitConfigFromDubbo 从 dubbo-go 配置中心获取限流配置
+func GetRateLimitConfigFromDubbo() *RateLimitConfig {
+	return defaultManager.GetRateLimitConfig()
+}
@@ -3,10 +3,8 @@ package config
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
-	"github.com/dubbogo/gost/log/logger"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -25,11 +23,12 @@ type RedisConfig struct {
 	IdleTimeout   string `json:"idle_timeout" yaml:"idle_timeout"`
 	IdleCheckFreq string `json:"idle_check_freq" yaml:"idle_check_freq"`
 	MaxConnAge    string `json:"max_conn_age" yaml:"max_conn_age"`
+	LogLevel      string `json:"log_level" yaml:"log_level"` // go-redis 内部日志级别：error, warn, info, debug
 }
 
-// CreateRedisClient 创建 Redis 客户端
-func (rc *RedisConfig) CreateRedisClient() (*redis.Client, error) {
-	redisClient := redis.NewClient(&redis.Options{
+// Options 转换为 go-redis 连接选项
+func (rc *RedisConfig) Options() *redis.Options {
+	return &redis.Options{
 		Addr:            rc.GetAddr(),
 		Password:        rc.Password,
 		DB:              rc.DB,
@@ -41,7 +40,12 @@ func (rc *RedisConfig) CreateRedisClient() (*redis.Client, error) {
 		PoolTimeout:     parseDuration(rc.PoolTimeout),
 		ConnMaxIdleTime: parseDuration(rc.IdleTimeout),
 		ConnMaxLifetime: parseDuration(rc.MaxConnAge),
-	})
+	}
+}
+
+// CreateRedisClient 创建 Redis 客户端
+func (rc *RedisConfig) CreateRedisClient() (*redis.Client, error) {
+	redisClient := redis.NewClient(rc.Options())
 
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), parseDuration(rc.ConnTimeout))
@@ -60,24 +64,19 @@ func (rc *RedisConfig) GetAddr() string {
 	return fmt.Sprintf("%s:%d", rc.Host, rc.Port)
 }
 
-// parseDuration 解析时间字符串为 time.Duration
+// parseDuration 解析时长字符串，支持 "3s" 和数字（秒）
 func parseDuration(s string) time.Duration {
-	if s == "" {
-		return 0
-	}
-	// 移除可能的引号
-	s = strings.Trim(s, `"`)
-	d, err := time.ParseDuration(s)
+	d, err := toDuration(s)
 	if err != nil {
-		logger.Errorf("Failed to parse duration %s: %v, using default 0", s, err)
+		logger.Errorf("Failed to parse duration %q: %v, using default 0", s, err)
 		return 0
 	}
 	return d
 }
 
-// GetRedisConfigFromDubbo 从 dubbo-go 配置中心获取 Redis 配置
-func GetRedisConfigFromDubbo() (*RedisConfig, error) {
-	configMap := GetStringMap("redis")
+// GetRedisConfig 获取 Redis 配置
+func (m *Manager) GetRedisConfig() (*RedisConfig, error) {
+	configMap := m.GetStringMap("redis")
 	if configMap == nil {
 		logger.Errorf("redis config not found")
 		return nil, fmt.Errorf("redis config not found")
@@ -91,47 +90,16 @@ func GetRedisConfigFromDubbo() (*RedisConfig, error) {
 func ParseRedisConfig(redisMap map[string]interface{}) (*RedisConfig, error) {
 	config := &RedisConfig{}
 
-	// 解析各个字段
-	if v, ok := redisMap["host"].(string); ok {
-		config.Host = v
-	}
-	if v, ok := redisMap["port"].(int); ok {
-		config.Port = v
-	}
-	if v, ok := redisMap["password"].(string); ok {
-		config.Password = v
-	}
-	if v, ok := redisMap["db"].(int); ok {
-		config.DB = v
-	}
-	if v, ok := redisMap["pool_size"].(int); ok {
-		config.PoolSize = v
-	}
-	if v, ok := redisMap["min_idle_conns"].(int); ok {
-		config.MinIdleConns = v
-	}
-	if v, ok := redisMap["conn_timeout"].(string); ok {
-		config.ConnTimeout = v
-	}
-	if v, ok := redisMap["read_timeout"].(string); ok {
-		config.ReadTimeout = v
-	}
-	if v, ok := redisMap["write_timeout"].(string); ok {
-		config.WriteTimeout = v
-	}
-	if v, ok := redisMap["pool_timeout"].(string); ok {
-		config.PoolTimeout = v
-	}
-	if v, ok := redisMap["idle_timeout"].(string); ok {
-		config.IdleTimeout = v
-	}
-	if v, ok := redisMap["idle_check_freq"].(string); ok {
-		config.IdleCheckFreq = v
-	}
-	if v, ok := redisMap["max_conn_age"].(string); ok {
-		config.MaxConnAge = v
+	// 解析各个字段，超时支持 "3s" 和数字（秒）
+	if err := bindValue("redis", redisMap, config); err != nil {
+		return nil, fmt.Errorf("invalid redis config: %w", err)
 	}
 
 	logger.Infof("Parsed Redis config: %+v", config)
 	return config, nil
 }
+
+// GetRedisConfigFromDubbo 从 dubbo-go 配置中心获取 Redis 配置
+func GetRedisConfigFromDubbo() (*RedisConfig, error) {
+	return defaultManager.GetRedisConfig()
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"time"
+)
+
+// applyRemote 处理配置来源推送的配置：记录历史 -> 校验 -> （灰度等待）-> 生效
+// 校验失败时保留当前配置；启动时校验失败则使用历史中最近一次生效的版本
+// 固定版本期间只记录不生效
+// 校验器（redis/mysql 试连最长 ValidationTimeout）在取得 applyMu 之前执行，不阻塞回滚和固定
+func (m *Manager) applyRemote(content string) error {
+	m.remoteMu.Lock()
+	defer m.remoteMu.Unlock()
+	defer m.notifyChangeListeners()
+
+	tree, err := m.parseConfig(content)
+	var base *configTree
+	var validateErr error
+	if err == nil {
+		base = m.tree.Load()
+		validateErr = m.validateAgainst(tree, base)
+	}
+
+	m.applyMu.Lock()
+	defer m.applyMu.Unlock()
+
+	source := m.source.Name()
+	if err != nil {
+		v := m.history.record(content, source, false)
+		m.history.markRejected(v.Version, err)
+		m.audit(v, AuditRejected, nil, err)
+		reloadTotal.WithLabelValues(reloadRejected).Inc()
+		return err
+	}
+
+	current, pinned, _ := m.history.state()
+	v := m.history.record(content, source, false)
+	m.history.setRemote(v)
+
+	if pinned != nil {
+		m.audit(v, AuditPinned, tree, nil)
+		logger.Warnf("Config is pinned to v%d, %s config v%d (hash=%s) recorded but not applied",
+			pinned.Version, source, v.Version, shortHash(v.Hash))
+		if current == 0 {
+			// 启动时使用固定的版本
+			return m.applyStored(*pinned)
+		}
+		return nil
+	}
+
+	startup := current == 0
+	if now := m.tree.Load(); now != base {
+		// 校验期间发生了回滚或固定，按新的当前配置重新校验
+		validateErr = m.validateAgainst(tree, now)
+	}
+	if err := validateErr; err != nil {
+		m.history.markRejected(v.Version, err)
+		m.audit(v, AuditRejected, tree, err)
+		reloadTotal.WithLabelValues(reloadRejected).Inc()
+		if !startup {
+			logger.Errorf("Rejected app config v%d (hash=%s), keeping v%d: %v", v.Version, shortHash(v.Hash), current, err)
+			return err
+		}
+		if good, ok := m.history.lastGood(); ok {
+			logger.Errorf("Rejected app config v%d (hash=%s), falling back to last known good v%d: %v",
+				v.Version, shortHash(v.Hash), good.Version, err)
+			reloadTotal.WithLabelValues(reloadFallback).Inc()
+			return m.applyStored(good)
+		}
+		logger.Errorf("App config v%d (hash=%s) is invalid and no last known good config is available, applying anyway: %v",
+			v.Version, shortHash(v.Hash), err)
+	}
+
+	if startup || m.reloadOpts.CanaryDelay <= 0 {
+		m.commitConfig(tree, v)
+		return nil
+	}
+	m.scheduleCanary(tree, v)
+	return nil
+}
+
+// validateAgainst 以 base 为当前配置执行校验器，base 尚未生效（启动时）传 nil
+func (m *Manager) validateAgainst(tree, base *configTree) error {
+	var current map[string]interface{}
+	if base.version != 0 {
+		current = base.data
+	}
+	return m.runValidators(tree.data, current)
+}
+
+// applyStored 应用历史中的版本（固定版本或最近一次生效的版本），调用方持有 applyMu
+func (m *Manager) applyStored(v ConfigVersion) error {
+	tree, err := m.parseConfig(v.Content)
+	if err != nil {
+		return err
+	}
+	m.history.setCurrent(v.Version)
+	m.applyConfig(tree, v)
+	return nil
+}
+
+// commitConfig 校验通过的配置生效，调用方持有 applyMu
+func (m *Manager) commitConfig(tree *configTree, v ConfigVersion) {
+	m.history.markApplied(v.Version)
+	m.applyConfig(tree, v)
+	reloadTotal.WithLabelValues(reloadApplied).Inc()
+}
+
+// scheduleCanary 校验通过后等待 CanaryDelay 再生效，期间收到新的推送则替代；调用方持有 applyMu
+func (m *Manager) scheduleCanary(tree *configTree, v ConfigVersion) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+
+	if m.pendingTimer != nil && m.pendingTimer.Stop() {
+		logger.Infof("Pending app config v%d superseded by v%d", m.pendingVersion, v.Version)
+		m.auditSuperseded(m.pendingVersion)
+		reloadTotal.WithLabelValues(reloadSuperseded).Inc()
+	}
+	m.pendingVersion = v.Version
+	logger.Infof("App config v%d (hash=%s) passed validation, applying in %s",
+		v.Version, shortHash(v.Hash), m.reloadOpts.CanaryDelay)
+
+	m.pendingTimer = time.AfterFunc(m.reloadOpts.CanaryDelay, func() {
+		defer m.notifyChangeListeners()
+		// 先取得 applyMu 再检查是否仍在等待：计时器触发后、取得锁之前发生的回滚或固定会清空 pendingVersion
+		m.applyMu.Lock()
+		defer m.applyMu.Unlock()
+
+		m.pendingMu.Lock()
+		if m.pendingVersion != v.Version {
+			m.pendingMu.Unlock()
+			return
+		}
+		m.pendingTimer = nil
+		m.pendingVersion = 0
+		m.pendingMu.Unlock()
+
+		if _, pinned, _ := m.history.state(); pinned != nil {
+			logger.Warnf("Config is pinned to v%d, pending config v%d not applied", pinned.Version, v.Version)
+			m.audit(v, AuditPinned, tree, nil)
+			return
+		}
+		m.commitConfig(tree, v)
+	})
+}
+
+// cancelPending 取消等待中的灰度配置，用于回滚和固定版本；调用方持有 applyMu
+func (m *Manager) cancelPending() {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	if m.pendingTimer != nil && m.pendingTimer.Stop() {
+		logger.Warnf("Pending app config v%d cancelled", m.pendingVersion)
+		m.auditSuperseded(m.pendingVersion)
+		reloadTotal.WithLabelValues(reloadSuperseded).Inc()
+	}
+	m.pendingTimer = nil
+	m.pendingVersion = 0
+}
+
+// auditSuperseded 记录未生效的灰度配置
+func (m *Manager) auditSuperseded(version int64) {
+	if v, err := m.history.get(version); err == nil {
+		m.audit(v, AuditSuperseded, nil, nil)
+	}
+}
+
+// pending 灰度等待中的版本，0 表示没有
+func (m *Manager) pending() int64 {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	if m.pendingTimer == nil {
+		return 0
+	}
+	return m.pendingVersion
+}
@@ -0,0 +1,333 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaType 配置项类型
+type SchemaType int
+
+const (
+	TypeAny      SchemaType = iota // 不校验
+	TypeString                     // 字符串
+	TypeInt                        // 整数，数字字符串按整数处理
+	TypeFloat                      // 数字（整数或小数）
+	TypeBool                       // 布尔，支持 "true"/"1"/"yes"/"on" 等字符串
+	TypeDuration                   // 时长："3s"、"500ms" 或数字（秒）
+	TypeObject                     // 固定字段的对象，出现未声明的字段报错
+	TypeMap                        // 任意 key 的对象，值使用 Items 校验
+	TypeList                       // 列表，元素使用 Items 校验
+)
+
+// String 类型名称，用于错误信息
+func (t SchemaType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "integer"
+	case TypeFloat:
+		return "number"
+	case TypeBool:
+		return "boolean"
+	case TypeDuration:
+		return "duration"
+	case TypeObject, TypeMap:
+		return "object"
+	case TypeList:
+		return "list"
+	default:
+		return "any"
+	}
+}
+
+// Schema 配置结构描述
+type Schema struct {
+	Type       SchemaType
+	Properties map[string]*Schema // TypeObject 的字段
+	Items      *Schema            // TypeMap 的值、TypeList 的元素
+	Enum       []string           // 字符串取值范围
+	Min        *float64           // 数字最小值，时长按秒比较
+	Max        *float64           // 数字最大值，时长按秒比较
+}
+
+// ValidationError 单个校验错误
+type ValidationError struct {
+	Path    string // 配置路径，如 mysql.port、log.outputs[0].type
+	Message string
+	Unknown bool // 未在 schema 中声明的 key，可能是拼写错误，也可能是新增的业务配置
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors 校验错误列表
+type ValidationErrors []ValidationError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate 校验配置值，返回所有错误（按路径排序）
+// 类型按读取时的转换规则判断，能转换的值（如整数字段的 "3306"）视为合法：properties 格式和拼接的占位符只能产生字符串
+func (s *Schema) Validate(value interface{}) ValidationErrors {
+	var errs ValidationErrors
+	s.validate("", value, &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs
+}
+
+// validate 递归校验
+func (s *Schema) validate(path string, value interface{}, errs *ValidationErrors) {
+	fail := func(format string, args ...interface{}) {
+		p := path
+		if p == "" {
+			p = "<root>"
+		}
+		*errs = append(*errs, ValidationError{Path: p, Message: fmt.Sprintf(format, args...)})
+	}
+	if value == nil {
+		// 写了 key 但没有值，按未配置处理
+		return
+	}
+
+	switch s.Type {
+	case TypeAny:
+		return
+	case TypeString:
+		v, err := toString(value)
+		if err != nil {
+			fail("expected string, got %s", typeName(value))
+			return
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, v) {
+			fail("invalid value %q, must be one of %s", v, strings.Join(s.Enum, ", "))
+		}
+	case TypeInt:
+		n, err := toInt64(value)
+		if err != nil {
+			fail("expected integer, got %s", typeName(value))
+			return
+		}
+		s.checkRange(float64(n), fail)
+	case TypeFloat:
+		n, err := toFloat64(value)
+		if err != nil {
+			fail("expected number, got %s", typeName(value))
+			return
+		}
+		s.checkRange(n, fail)
+	case TypeBool:
+		if _, err := toBool(value); err != nil {
+			fail("expected boolean, got %s", typeName(value))
+		}
+	case TypeDuration:
+		d, err := toDuration(value)
+		if err != nil {
+			if v, ok := value.(string); ok {
+				fail("invalid duration %q", v)
+			} else {
+				fail("expected duration (e.g. \"3s\") or seconds, got %s", typeName(value))
+			}
+			return
+		}
+		s.checkRange(d.Seconds(), fail)
+	case TypeObject:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %s", typeName(value))
+			return
+		}
+		for k, v := range m {
+			child, ok := s.Properties[k]
+			if !ok {
+				*errs = append(*errs, ValidationError{Path: joinPath(path, k), Message: "unknown key" + suggest(k, s.Properties), Unknown: true})
+				continue
+			}
+			child.validate(joinPath(path, k), v, errs)
+		}
+	case TypeMap:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %s", typeName(value))
+			return
+		}
+		if s.Items != nil {
+			for k, v := range m {
+				s.Items.validate(joinPath(path, k), v, errs)
+			}
+		}
+	case TypeList:
+		list, ok := value.([]interface{})
+		if !ok {
+			fail("expected list, got %s", typeName(value))
+			return
+		}
+		if s.Items != nil {
+			for i, v := range list {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), v, errs)
+			}
+		}
+	}
+}
+
+// quotedScalars 返回写成字符串的整数、数字和布尔值（如 port: "3306"），按路径排序
+// 这些值能按类型转换规则读取，Validate 不报告；YAML/JSON/TOML 中通常是多写了引号，check-config 作为警告输出
+// 含占位符的字符串展开后才确定类型，不报告
+func (s *Schema) quotedScalars(value interface{}) ValidationErrors {
+	var found ValidationErrors
+	s.findQuoted("", value, &found)
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Path < found[j].Path })
+	return found
+}
+
+// findQuoted 递归查找写成字符串的标量
+func (s *Schema) findQuoted(path string, value interface{}, found *ValidationErrors) {
+	switch v := value.(type) {
+	case string:
+		if strings.Contains(v, "${") {
+			return
+		}
+		var err error
+		switch s.Type {
+		case TypeInt:
+			_, err = toInt64(v)
+		case TypeFloat:
+			_, err = toFloat64(v)
+		case TypeBool:
+			_, err = toBool(v)
+		default:
+			return
+		}
+		if err == nil {
+			*found = append(*found, ValidationError{Path: path, Message: fmt.Sprintf("%s written as string %q", s.Type, v)})
+		}
+	case map[string]interface{}:
+		for k, child := range v {
+			switch {
+			case s.Type == TypeObject && s.Properties[k] != nil:
+				s.Properties[k].findQuoted(joinPath(path, k), child, found)
+			case s.Type == TypeMap && s.Items != nil:
+				s.Items.findQuoted(joinPath(path, k), child, found)
+			}
+		}
+	case []interface{}:
+		if s.Type == TypeList && s.Items != nil {
+			for i, child := range v {
+				s.Items.findQuoted(fmt.Sprintf("%s[%d]", path, i), child, found)
+			}
+		}
+	}
+}
+
+// checkRange 校验数值范围
+func (s *Schema) checkRange(n float64, fail func(string, ...interface{})) {
+	if s.Min != nil && n < *s.Min {
+		fail("value %v is less than minimum %v", n, *s.Min)
+	}
+	if s.Max != nil && n > *s.Max {
+		fail("value %v is greater than maximum %v", n, *s.Max)
+	}
+}
+
+// joinPath 拼接配置路径
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// toNumber 将 YAML/JSON 解析出的数字统一为 float64
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// typeName 错误信息中使用的类型名
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return "boolean"
+	case int, int64, uint64:
+		return "integer"
+	case float64, float32:
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "list"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// contains 判断字符串是否在列表中
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest 为拼写错误的 key 给出最接近的已知 key
+func suggest(key string, props map[string]*Schema) string {
+	best, bestDist := "", 3
+	for k := range props {
+		d := editDistance(key, k)
+		if d*2 >= len(key) {
+			// 差异太大，不算拼写错误
+			continue
+		}
+		if d < bestDist || (d == bestDist && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// editDistance 编辑距离
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -4,9 +4,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
-	"github.com/dubbogo/gost/log/logger"
+	"helloworld/pkg/buildinfo"
 )
 
 // NacosConfig Nacos 配置结构体
@@ -21,9 +22,18 @@ type NacosConfig struct {
 // Config 应用配置结构体
 type Config struct {
 	Nacos    NacosConfig
+	Profile  Profile // 运行环境，未指定时 Name 为空
 	AppName  string
 	AppPort  int
 	LogLevel string
+
+	AdminAddr    string         // 管理端监听地址，"off" 表示不启动
+	AdminToken   string         // 管理端回滚、固定等接口的 token，为空时这些接口不可用
+	ConfigFile   string         // 本地应用配置文件，设置后不从 Nacos 读取应用配置
+	ConfigFormat string         // 应用配置格式，为空时按 data ID 扩展名判断
+	History      HistoryOptions // 应用配置历史
+	Reload       ReloadOptions  // 应用配置更新
+	Audit        AuditOptions   // 配置变更审计和 webhook 通知
 }
 
 // defaultNacosConfig 默认 Nacos 配置
@@ -35,20 +45,32 @@ var defaultNacosConfig = NacosConfig{
 	Timeout:   "3s",
 }
 
-// ParseConfig 解析配置，优先级：命令行参数 > 环境变量 > 默认值
+// ParseConfig 解析配置，优先级：命令行参数 > 环境变量 > profile > 默认值
 func ParseConfig() (*Config, error) {
 	// 定义命令行参数
 	var (
-		nacosAddr   = flag.String("nacos-addr", "", "Nacos server address")
-		namespace   = flag.String("namespace", "", "Nacos namespace")
-		group       = flag.String("group", "", "Nacos group")
-		dataID      = flag.String("data-id", "", "Nacos config data ID")
-		timeout     = flag.String("timeout", "", "Nacos timeout")
-		appName     = flag.String("app-name", "", "Application name")
-		appPort     = flag.Int("port", 0, "Application port")
-		logLevel    = flag.String("log-level", "", "Log level")
-		showVersion = flag.Bool("version", false, "Show version")
-		help        = flag.Bool("help", false, "Show help")
+		profile      = flag.String("profile", "", "Environment profile: dev, test, staging, prod")
+		profilesFile = flag.String("profiles-file", "", "Profiles file overriding the built-in profile table")
+		nacosAddr    = flag.String("nacos-addr", "", "Nacos server address")
+		namespace    = flag.String("namespace", "", "Nacos namespace")
+		group        = flag.String("group", "", "Nacos group")
+		dataID       = flag.String("data-id", "", "Nacos config data ID")
+		timeout      = flag.String("timeout", "", "Nacos timeout")
+		appName      = flag.String("app-name", "", "Application name")
+		appPort      = flag.Int("port", 0, "Application port")
+		logLevel     = flag.String("log-level", "", "Log level")
+		adminAddr    = flag.String("admin-addr", "", "Admin server address")
+		adminToken   = flag.String("admin-token", "", "Token for admin rollback/pin/unpin endpoints (prefer ADMIN_TOKEN)")
+		configFile   = flag.String("config-file", "", "Local app config file, watched for changes (replaces Nacos)")
+		configFormat = flag.String("config-format", "", "App config format: yaml, json, properties, toml")
+		historyDir   = flag.String("config-history-dir", "", "Directory for app config history")
+		historySize  = flag.Int("config-history-size", 0, "Number of app config versions to keep")
+		canaryDelay  = flag.String("config-canary-delay", "", "Delay before applying validated app config")
+		auditFile    = flag.String("audit-file", "", "Audit log file for app config changes, off to disable")
+		auditWebhook = flag.String("audit-webhook", "", "Webhook URL for config change audit and component state events")
+		auditRetries = flag.Int("audit-webhook-retries", 0, "Webhook retries after a failed delivery")
+		showVersion  = flag.Bool("version", false, "Show version")
+		help         = flag.Bool("help", false, "Show help")
 	)
 
 	flag.Parse()
@@ -58,43 +80,97 @@ func ParseConfig() (*Config, error) {
 		printHelp()
 	}
 	if *showVersion {
-		fmt.Printf("version 1.0.0\n")
+		fmt.Println(buildinfo.String())
 		os.Exit(0)
 	}
 
-	// 构建配置，按照优先级处理
+	// 构建配置，按照优先级处理：命令行参数 > 环境变量 > profile 默认值
+	if err := LoadProfiles(getStringValue(*profilesFile, getEnv("APP_PROFILES_FILE"), "")); err != nil {
+		return nil, err
+	}
+	p, err := LookupProfile(getStringValue(*profile, getEnv("APP_PROFILE"), ""))
+	if err != nil {
+		return nil, err
+	}
 	config := &Config{
-		Nacos: defaultNacosConfig,
+		Nacos:   defaultNacosConfig,
+		Profile: p,
 	}
 
 	// 设置应用相关配置
 	config.AppName = getStringValue(*appName, getEnv("APP_NAME"), "")
 	config.AppPort = getIntValue(*appPort, getEnvInt("APP_PORT"), 20001)
-	config.LogLevel = getStringValue(*logLevel, getEnv("LOG_LEVEL"), "info")
+	config.LogLevel = getStringValue(*logLevel, getEnv("LOG_LEVEL"), p.LogLevel)
+	config.AdminAddr = getStringValue(*adminAddr, getEnv("ADMIN_ADDR"), "127.0.0.1:20002")
+	if config.AdminAddr == "off" {
+		config.AdminAddr = ""
+	}
+	config.AdminToken = getStringValue(*adminToken, getEnv("ADMIN_TOKEN"), "")
+	config.ConfigFile = getStringValue(*configFile, getEnv("CONFIG_FILE"), "")
+	config.ConfigFormat = strings.ToLower(getStringValue(*configFormat, getEnv("CONFIG_FORMAT"), ""))
+	if config.ConfigFormat != "" {
+		if _, err := lookupDecoder(config.ConfigFormat); err != nil {
+			return nil, err
+		}
+	}
+	config.History.Dir = getStringValue(*historyDir, getEnv("CONFIG_HISTORY_DIR"),
+		filepath.Join("data", "config-history", strings.ToLower(config.AppName)))
+	config.History.Size = getIntValue(*historySize, getEnvInt("CONFIG_HISTORY_SIZE"), 10)
+	if delay := getStringValue(*canaryDelay, getEnv("CONFIG_CANARY_DELAY"), ""); delay != "" {
+		d, err := toDuration(delay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config canary delay %q: %w", delay, err)
+		}
+		config.Reload.CanaryDelay = d
+	}
+
+	config.Audit.File = getStringValue(*auditFile, getEnv("AUDIT_FILE"),
+		filepath.Join("data", "audit", strings.ToLower(config.AppName)+".log"))
+	if config.Audit.File == "off" {
+		config.Audit.File = ""
+	}
+	config.Audit.WebhookURL = getStringValue(*auditWebhook, getEnv("AUDIT_WEBHOOK"), "")
+	config.Audit.WebhookRetries = getIntValue(*auditRetries, getEnvInt("AUDIT_WEBHOOK_RETRIES"), 3)
 
 	// 设置 Nacos 相关配置
-	config.Nacos.Address = getStringValue(*nacosAddr, getEnv("NACOS_ADDR"), defaultNacosConfig.Address)
-	config.Nacos.Namespace = getStringValue(*namespace, getEnv("NACOS_NAMESPACE"), defaultNacosConfig.Namespace)
-	config.Nacos.Group = getStringValue(*group, getEnv("NACOS_GROUP"), defaultNacosConfig.Group)
+	config.Nacos.Address = getStringValue(*nacosAddr, getEnv("NACOS_ADDR"), p.RegistryAddr)
+	config.Nacos.Namespace = getStringValue(*namespace, getEnv("NACOS_NAMESPACE"), p.Namespace)
+	config.Nacos.Group = getStringValue(*group, getEnv("NACOS_GROUP"), p.Group)
 	config.Nacos.Timeout = getStringValue(*timeout, getEnv("NACOS_TIMEOUT"), defaultNacosConfig.Timeout)
-
-	// DataID 需要特殊处理
-	if *dataID != "" {
-		config.Nacos.DataID = *dataID
-	} else if envDataID := getEnv("NACOS_DATA_ID"); envDataID != "" {
-		config.Nacos.DataID = envDataID
-	} else {
-		config.Nacos.DataID = fmt.Sprintf("%s-config", strings.ToLower(config.AppName))
-	}
+	config.Nacos.DataID = getStringValue(*dataID, getEnv("NACOS_DATA_ID"), p.DataID(config.AppName))
 
 	// 验证必要配置
 	if config.Nacos.Address == "" {
 		return nil, fmt.Errorf("nacos address is required")
 	}
+	if err := checkProfileTarget(p, config.Nacos.Namespace, config.Nacos.Address); err != nil {
+		return nil, err
+	}
+	SetProfile(p)
 
+	logger.Infof("Starting %s", buildinfo.String())
+	logger.Infof("Profile: %s (namespace=%s, group=%s, data-id=%s, nacos=%s, log-level=%s)",
+		profileName(p), config.Nacos.Namespace, config.Nacos.Group, config.Nacos.DataID, config.Nacos.Address, config.LogLevel)
 	return config, nil
 }
 
+// Redacted 用于输出日志的副本，隐藏 token
+func (c *Config) Redacted() Config {
+	cp := *c
+	if cp.AdminToken != "" {
+		cp.AdminToken = redacted
+	}
+	return cp
+}
+
+// profileName 日志中显示的 profile 名称
+func profileName(p Profile) string {
+	if p.Name == "" {
+		return "none"
+	}
+	return p.Name
+}
+
 // getStringValue 获取字符串值，按优先级：命令行 > 环境变量 > 默认值
 func getStringValue(flagVal, envVal, defaultVal string) string {
 	if flagVal != "" {
@@ -145,18 +221,32 @@ func printHelp() {
 	logger.Info(fmt.Sprintf("Usage [options]"))
 	logger.Info("")
 	logger.Info("Options:")
+	logger.Info("  -profile string       Environment profile: dev, test, staging, prod (sets Nacos namespace, group, data ID, address and log level defaults)")
+	logger.Info("  -profiles-file string Profiles file (yaml/json) overriding namespace, group, data ID pattern, address or log level per profile")
 	logger.Info("  -nacos-addr string    Nacos server address (e.g., 192.168.139.230:8848)")
-	logger.Info("  -namespace string     Nacos namespace (default: public)")
+	logger.Info("  -namespace string     Nacos namespace (default: profile namespace, or public)")
 	logger.Info("  -group string         Nacos group (default: DEFAULT_GROUP)")
-	logger.Info("  -data-id string       Nacos config data ID")
+	logger.Info("  -data-id string       Nacos config data ID (default: app name)")
 	logger.Info("  -timeout string       Nacos timeout (default: 3s)")
 	logger.Info("  -app-name string      Application name")
 	logger.Info(fmt.Sprintf("  -port int             Application port (server default: 20001)"))
-	logger.Info("  -log-level string     Log level (default: info)")
-	logger.Info("  -version              Show version")
+	logger.Info("  -log-level string     Log level when app config has no log.level (default: profile level, or info)")
+	logger.Info("  -admin-addr string    Admin server address, off to disable (default: 127.0.0.1:20002)")
+	logger.Info("  -admin-token string   Token for admin rollback/pin/unpin, disabled when empty (prefer ADMIN_TOKEN)")
+	logger.Info("  -config-file string   Local app config file watched for changes, e.g. /etc/helloworld/app.yaml (replaces Nacos)")
+	logger.Info("  -config-format        App config format: yaml, json, properties, toml (default: data ID extension, else yaml)")
+	logger.Info("  -config-history-dir   App config history directory (default: data/config-history/<app-name>)")
+	logger.Info("  -config-history-size  App config versions to keep (default: 10)")
+	logger.Info("  -config-canary-delay  Delay before applying validated app config, e.g. 2m (default: 0)")
+	logger.Info("  -audit-file           Audit log for app config changes, off to disable (default: data/audit/<app-name>.log)")
+	logger.Info("  -audit-webhook        Webhook URL for config change audit and component state events")
+	logger.Info("  -audit-webhook-retries Webhook retries after a failed delivery (default: 3)")
+	logger.Info("  -version              Show version, git commit and build time")
 	logger.Info("  -help                 Show this help")
 	logger.Info("")
 	logger.Info("Environment Variables:")
+	logger.Info("  APP_PROFILE           Environment profile")
+	logger.Info("  APP_PROFILES_FILE     Profiles file")
 	logger.Info("  NACOS_ADDR            Nacos server address")
 	logger.Info("  NACOS_NAMESPACE       Nacos namespace")
 	logger.Info("  NACOS_GROUP           Nacos group")
@@ -165,6 +255,19 @@ func printHelp() {
 	logger.Info("  APP_NAME              Application name")
 	logger.Info("  APP_PORT              Application port")
 	logger.Info("  LOG_LEVEL             Log level")
+	logger.Info("  ADMIN_ADDR            Admin server address")
+	logger.Info("  ADMIN_TOKEN           Admin token for rollback/pin/unpin")
+	logger.Info("  CONFIG_FILE           Local app config file")
+	logger.Info("  CONFIG_FORMAT         App config format")
+	logger.Info("  CONFIG_HISTORY_DIR    App config history directory")
+	logger.Info("  CONFIG_HISTORY_SIZE   App config versions to keep")
+	logger.Info("  CONFIG_CANARY_DELAY   Delay before applying validated app config")
+	logger.Info("  AUDIT_FILE            Audit log file")
+	logger.Info("  AUDIT_WEBHOOK         Audit webhook URL")
+	logger.Info("  AUDIT_WEBHOOK_RETRIES Audit webhook retries")
+	logger.Info("")
+	logger.Info("Priority: Command Line > Environment Variables > Profile > Defaults")
 	logger.Info("")
-	logger.Info("Priority: Command Line > Environment Variables > Defaults")
+	logger.Info("Commands:")
+	logger.Info("  check-config          Validate app config: check-config -file app.yaml | -data-id go-server")
 }
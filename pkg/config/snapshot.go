@@ -0,0 +1,179 @@
+package config
+
+import "time"
+
+// ConfigSnapshot 某一版本配置的只读视图，创建后不受配置更新影响
+// 一次请求内需要读取多个相关配置（如 redis.host 和 redis.port）时使用，避免读到新旧混合的配置
+// 读取方法与 Manager 相同，Get 返回的 map 和切片只能读取，GetStringMap、GetAll、Bind 返回副本
+type ConfigSnapshot struct {
+	tree *configTree
+}
+
+// Snapshot 返回当前生效配置的快照
+func (m *Manager) Snapshot() *ConfigSnapshot {
+	return &ConfigSnapshot{tree: m.tree.Load()}
+}
+
+// Snapshot 返回默认管理器当前生效配置的快照
+func Snapshot() *ConfigSnapshot {
+	return defaultManager.Snapshot()
+}
+
+// Version 快照对应的配置版本号，尚未加载配置时为 0
+func (s *ConfigSnapshot) Version() int64 {
+	return s.tree.version
+}
+
+// Hash 快照对应的配置内容 sha256，尚未加载配置时为空
+func (s *ConfigSnapshot) Hash() string {
+	return s.tree.hash
+}
+
+// Overrides 快照中命中的 overrides id
+func (s *ConfigSnapshot) Overrides() []string {
+	return append([]string(nil), s.tree.overrides...)
+}
+
+// currentTree 快照对应的配置树
+func (s *ConfigSnapshot) currentTree() *configTree {
+	return s.tree
+}
+
+// Get 获取配置值（支持点号路径，如 "redis.host"），不复制，返回的 map 和切片只能读取
+func (s *ConfigSnapshot) Get(key string) interface{} {
+	return lookup(s.tree.data, key)
+}
+
+// GetStringMap 获取map配置，返回副本
+func (s *ConfigSnapshot) GetStringMap(key string) map[string]interface{} {
+	sm, _ := s.Get(key).(map[string]interface{})
+	return deepCopyMap(sm)
+}
+
+// IsSet 检查配置是否存在
+func (s *ConfigSnapshot) IsSet(key string) bool {
+	return lookup(s.tree.data, key) != nil
+}
+
+// GetAll 获取所有配置的深拷贝
+func (s *ConfigSnapshot) GetAll() map[string]interface{} {
+	return deepCopyMap(s.tree.data)
+}
+
+// Bind 将配置项绑定到结构体，规则同 Manager.Bind
+func (s *ConfigSnapshot) Bind(key string, out interface{}) error {
+	return bindKey(s, key, out)
+}
+
+// GetString 获取字符串配置，数字和布尔按字面值转换
+func (s *ConfigSnapshot) GetString(key string) string { return get(s, key, toString) }
+
+// GetStringOr 获取字符串配置，不存在时返回 def
+func (s *ConfigSnapshot) GetStringOr(key, def string) string { return getOr(s, key, def, toString) }
+
+// GetStringE 获取字符串配置，不存在或无法转换时返回错误
+func (s *ConfigSnapshot) GetStringE(key string) (string, error) { return getE(s, key, toString) }
+
+// GetInt 获取整数配置，支持数字字符串
+func (s *ConfigSnapshot) GetInt(key string) int { return get(s, key, toInt) }
+
+// GetIntOr 获取整数配置，不存在时返回 def
+func (s *ConfigSnapshot) GetIntOr(key string, def int) int { return getOr(s, key, def, toInt) }
+
+// GetIntE 获取整数配置，不存在或无法转换时返回错误
+func (s *ConfigSnapshot) GetIntE(key string) (int, error) { return getE(s, key, toInt) }
+
+// GetFloat64 获取浮点数配置
+func (s *ConfigSnapshot) GetFloat64(key string) float64 { return get(s, key, toFloat64) }
+
+// GetFloat64Or 获取浮点数配置，不存在时返回 def
+func (s *ConfigSnapshot) GetFloat64Or(key string, def float64) float64 {
+	return getOr(s, key, def, toFloat64)
+}
+
+// GetFloat64E 获取浮点数配置，不存在或无法转换时返回错误
+func (s *ConfigSnapshot) GetFloat64E(key string) (float64, error) { return getE(s, key, toFloat64) }
+
+// GetBool 获取布尔配置，支持 "true"/"1"/"yes"/"on" 等字符串
+func (s *ConfigSnapshot) GetBool(key string) bool { return get(s, key, toBool) }
+
+// GetBoolOr 获取布尔配置，不存在时返回 def
+func (s *ConfigSnapshot) GetBoolOr(key string, def bool) bool { return getOr(s, key, def, toBool) }
+
+// GetBoolE 获取布尔配置，不存在或无法转换时返回错误
+func (s *ConfigSnapshot) GetBoolE(key string) (bool, error) { return getE(s, key, toBool) }
+
+// GetDuration 获取时长配置，支持 "3s" 或数字（秒）
+func (s *ConfigSnapshot) GetDuration(key string) time.Duration { return get(s, key, toDuration) }
+
+// GetDurationOr 获取时长配置，不存在时返回 def
+func (s *ConfigSnapshot) GetDurationOr(key string, def time.Duration) time.Duration {
+	return getOr(s, key, def, toDuration)
+}
+
+// GetDurationE 获取时长配置，不存在或无法转换时返回错误
+func (s *ConfigSnapshot) GetDurationE(key string) (time.Duration, error) {
+	return getE(s, key, toDuration)
+}
+
+// GetTime 获取时间配置，支持 RFC3339、"2006-01-02 15:04:05"、"2006-01-02" 或 Unix 秒
+func (s *ConfigSnapshot) GetTime(key string) time.Time { return get(s, key, toTime) }
+
+// GetTimeOr 获取时间配置，不存在时返回 def
+func (s *ConfigSnapshot) GetTimeOr(key string, def time.Time) time.Time {
+	return getOr(s, key, def, toTime)
+}
+
+// GetTimeE 获取时间配置，不存在或无法转换时返回错误
+func (s *ConfigSnapshot) GetTimeE(key string) (time.Time, error) { return getE(s, key, toTime) }
+
+// GetStringSlice 获取字符串列表配置，支持 YAML 列表或逗号分隔的字符串
+func (s *ConfigSnapshot) GetStringSlice(key string) []string { return get(s, key, toStringSlice) }
+
+// GetStringSliceOr 获取字符串列表配置，不存在时返回 def
+func (s *ConfigSnapshot) GetStringSliceOr(key string, def []string) []string {
+	return getOr(s, key, def, toStringSlice)
+}
+
+// GetStringSliceE 获取字符串列表配置，不存在或无法转换时返回错误
+func (s *ConfigSnapshot) GetStringSliceE(key string) ([]string, error) {
+	return getE(s, key, toStringSlice)
+}
+
+// GetIntSlice 获取整数列表配置，支持 YAML 列表或逗号分隔的字符串
+func (s *ConfigSnapshot) GetIntSlice(key string) []int { return get(s, key, toIntSlice) }
+
+// GetIntSliceOr 获取整数列表配置，不存在时返回 def
+func (s *ConfigSnapshot) GetIntSliceOr(key string, def []int) []int {
+	return getOr(s, key, def, toIntSlice)
+}
+
+// GetIntSliceE 获取整数列表配置，不存在或无法转换时返回错误
+func (s *ConfigSnapshot) GetIntSliceE(key string) ([]int, error) { return getE(s, key, toIntSlice) }
+
+// deepCopyMap 深拷贝配置 map
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = deepCopyValue(v)
+	}
+	return result
+}
+
+// deepCopyValue 深拷贝配置值，标量原样返回
+func deepCopyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return deepCopyMap(val)
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, item := range val {
+			list[i] = deepCopyValue(item)
+		}
+		return list
+	}
+	return v
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+)
+
+// Source 应用配置来源
+type Source interface {
+	// Name 来源名称，记录在配置历史中，如 nacos
+	Name() string
+	// Load 读取当前配置内容
+	Load() (string, error)
+	// Watch 监听配置变化，内容变化时调用 onChange，Manager.Start 中只调用一次
+	Watch(onChange func(content string)) error
+}
+
+// formatSource 能根据 data ID 或文件名判断配置格式的 Source
+type formatSource interface {
+	Format() string
+}
+
+// NacosSource 通过 dubbo-go 配置中心读取 Nacos 上的配置
+type NacosSource struct {
+	dynamicConfig config_center.DynamicConfiguration
+	dataID        string
+	group         string
+}
+
+// NewNacosSource 创建 Nacos 配置来源，dubbo-go 配置中心未启动时返回错误
+func NewNacosSource(dataID, group string) (*NacosSource, error) {
+	dynamicConfig := conf.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfig == nil {
+		return nil, fmt.Errorf("dubbo config center is not started")
+	}
+	return &NacosSource{dynamicConfig: dynamicConfig, dataID: dataID, group: group}, nil
+}
+
+// Name 实现 Source 接口
+func (s *NacosSource) Name() string {
+	return SourceNacos
+}
+
+// Format 按 data ID 的扩展名判断配置格式，如 go-server.json
+func (s *NacosSource) Format() string {
+	return FormatFromDataID(s.dataID)
+}
+
+// Location 配置的 data ID 和分组
+func (s *NacosSource) Location() (dataID, group string) {
+	return s.dataID, s.group
+}
+
+// Load 实现 Source 接口
+func (s *NacosSource) Load() (string, error) {
+	return s.dynamicConfig.GetProperties(s.dataID, config_center.WithGroup(s.group))
+}
+
+// Watch 实现 Source 接口
+func (s *NacosSource) Watch(onChange func(content string)) error {
+	s.dynamicConfig.AddListener(s.dataID, &nacosListener{onChange: onChange}, config_center.WithGroup(s.group))
+	return nil
+}
+
+// nacosListener 配置监听器
+type nacosListener struct {
+	onChange func(content string)
+}
+
+// Process 实现 ConfigurationListener 接口
+func (l *nacosListener) Process(event *config_center.ConfigChangeEvent) {
+	logger.Infof("App config changed: key=%s, type=%v", event.Key, event.ConfigType)
+
+	valueStr, ok := event.Value.(string)
+	if !ok {
+		logger.Errorf("Failed to convert config value to string")
+		return
+	}
+	l.onChange(valueStr)
+}
+
+// MemorySource 内存配置来源，用于测试和本地调试，Set 会同步触发配置更新
+type MemorySource struct {
+	mu       sync.Mutex
+	content  string
+	watchers []func(string)
+}
+
+// NewMemorySource 创建内存配置来源
+func NewMemorySource(content string) *MemorySource {
+	return &MemorySource{content: content}
+}
+
+// Name 实现 Source 接口
+func (s *MemorySource) Name() string {
+	return "memory"
+}
+
+// Load 实现 Source 接口
+func (s *MemorySource) Load() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.content, nil
+}
+
+// Watch 实现 Source 接口
+func (s *MemorySource) Watch(onChange func(content string)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers = append(s.watchers, onChange)
+	return nil
+}
+
+// Set 更新配置内容并通知监听者
+func (s *MemorySource) Set(content string) {
+	s.mu.Lock()
+	s.content = content
+	watchers := make([]func(string), len(s.watchers))
+	copy(watchers, s.watchers)
+	s.mu.Unlock()
+
+	for _, w := range watchers {
+		w(content)
+	}
+}
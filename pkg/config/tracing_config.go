@@ -0,0 +1,37 @@
+package config
+
+// TracingConfig 链路追踪配置
+type TracingConfig struct {
+	Exporter     string  `json:"exporter" yaml:"exporter"`           // otlp | stdout | memory | none
+	Endpoint     string  `json:"endpoint" yaml:"endpoint"`           // OTLP 接收端地址，如 127.0.0.1:4317
+	Protocol     string  `json:"protocol" yaml:"protocol"`           // OTLP 协议: grpc | http
+	Insecure     bool    `json:"insecure" yaml:"insecure"`           // OTLP 是否使用明文连接
+	SamplerRatio float64 `json:"sampler_ratio" yaml:"sampler_ratio"` // 采样比例(0~1)，上游已采样的请求始终采样
+}
+
+// GetTracingConfig 获取链路追踪配置，未配置时使用默认值
+func (m *Manager) GetTracingConfig() *TracingConfig {
+	config := &TracingConfig{
+		Exporter:     "none",
+		Endpoint:     "127.0.0.1:4317",
+		Protocol:     "grpc",
+		Insecure:     true,
+		SamplerRatio: 1,
+	}
+
+	tracingMap := m.GetStringMap("tracing")
+	if tracingMap == nil {
+		return config
+	}
+
+	if err := bindValue("tracing", tracingMap, config); err != nil {
+		logger.Warnf("Invalid tracing config, keeping defaults for invalid fields: %v", err)
+	}
+
+	return config
+}
+
+// GetTracingConfigFromDubbo 从 dubbo-go 配置中心获取链路追踪配置
+func GetTracingConfigFromDubbo() *TracingConfig {
+	return defaultManager.GetTracingConfig()
+}
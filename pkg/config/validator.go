@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Validator 配置校验器，在新配置生效前执行，返回错误时拒绝新配置并保留当前配置
+// current 为当前生效的配置，启动时为 nil
+type Validator func(ctx context.Context, next, current map[string]interface{}) error
+
+// namedValidator 带名称的校验器，名称用于日志和指标
+type namedValidator struct {
+	name string
+	fn   Validator
+}
+
+// ReloadOptions 配置更新选项
+type ReloadOptions struct {
+	CanaryDelay       time.Duration // 校验通过后延迟生效，用于先在部分实例上观察新配置，0 表示立即生效
+	ValidationTimeout time.Duration // 所有校验器的总超时，默认 10s
+}
+
+// defaultValidators 内置校验器
+func defaultValidators() []namedValidator {
+	return []namedValidator{
+		{name: "profile", fn: validateProfile},
+		{name: "schema", fn: validateSchema},
+		{name: "flags", fn: validateFlags},
+		{name: "redis", fn: validateRedisDryRun},
+		{name: "mysql", fn: validateMySQLDryRun},
+	}
+}
+
+// normalizeReloadOptions 补全默认值
+func normalizeReloadOptions(opts ReloadOptions) ReloadOptions {
+	if opts.ValidationTimeout <= 0 {
+		opts.ValidationTimeout = 10 * time.Second
+	}
+	return opts
+}
+
+// RegisterValidator 注册配置校验器，在内置校验器之后按注册顺序执行，任一失败即拒绝
+func (m *Manager) RegisterValidator(name string, v Validator) {
+	m.validatorsMu.Lock()
+	defer m.validatorsMu.Unlock()
+	m.validators = append(m.validators, namedValidator{name: name, fn: v})
+}
+
+// RegisterValidator 为默认管理器注册配置校验器
+func RegisterValidator(name string, v Validator) {
+	defaultManager.RegisterValidator(name, v)
+}
+
+// runValidators 依次执行校验器，返回第一个错误
+func (m *Manager) runValidators(next, current map[string]interface{}) error {
+	m.validatorsMu.RLock()
+	list := make([]namedValidator, len(m.validators))
+	copy(list, m.validators)
+	m.validatorsMu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.reloadOpts.ValidationTimeout)
+	defer cancel()
+	for _, v := range list {
+		if err := v.fn(ctx, next, current); err != nil {
+			validationFailures.WithLabelValues(v.name).Inc()
+			return fmt.Errorf("validator %s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+// validateSchema 按 AppSchema 校验，未声明的 key 只输出警告
+func validateSchema(_ context.Context, next, _ map[string]interface{}) error {
+	var errs, unknown ValidationErrors
+	for _, e := range AppSchema().Validate(next) {
+		if e.Unknown {
+			unknown = append(unknown, e)
+		} else {
+			errs = append(errs, e)
+		}
+	}
+	if len(unknown) > 0 {
+		logger.Warnf("App config has unknown keys: %v", unknown)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateFlags 检查开关定义，schema 只校验字段类型，类型与 variants、default 不一致等只在这里发现
+func validateFlags(_ context.Context, next, _ map[string]interface{}) error {
+	if errs := checkFlags(next); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// sectionChanged 判断配置段是否需要试连：启动时由组件初始化负责，未配置或未变化时跳过
+func sectionChanged(key string, next, current map[string]interface{}) (map[string]interface{}, bool) {
+	if current == nil {
+		return nil, false
+	}
+	section, ok := next[key].(map[string]interface{})
+	if !ok || reflect.DeepEqual(next[key], current[key]) {
+		return nil, false
+	}
+	return section, true
+}
+
+// validateRedisDryRun redis 配置变化时用新配置建立连接并 PING
+func validateRedisDryRun(ctx context.Context, next, current map[string]interface{}) error {
+	section, ok := sectionChanged("redis", next, current)
+	if !ok {
+		return nil
+	}
+	rc, err := ParseRedisConfig(section)
+	if err != nil {
+		return err
+	}
+	client := redis.NewClient(rc.Options())
+	defer client.Close()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis %s ping failed: %w", rc.GetAddr(), err)
+	}
+	return nil
+}
+
+// validateMySQLDryRun mysql 配置变化时用新配置建立连接并 PING
+func validateMySQLDryRun(ctx context.Context, next, current map[string]interface{}) error {
+	section, ok := sectionChanged("mysql", next, current)
+	if !ok {
+		return nil
+	}
+	mc, err := ParseMySQLConfig(section)
+	if err != nil {
+		return err
+	}
+	db, err := sql.Open("mysql", mc.DSN())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("mysql %s:%d ping failed: %w", mc.Host, mc.Port, err)
+	}
+	return nil
+}
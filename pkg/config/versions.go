@@ -0,0 +1,177 @@
+package config
+
+import "fmt"
+
+// VersionStatus 配置版本状态
+type VersionStatus struct {
+	Current int64  `json:"current"`           // 当前生效的版本
+	Remote  int64  `json:"remote"`            // 最近一次配置来源推送的版本
+	Pinned  int64  `json:"pinned,omitempty"`  // 固定的版本，0 表示未固定
+	Pending int64  `json:"pending,omitempty"` // 校验通过、灰度等待中的版本
+	Hash    string `json:"hash,omitempty"`    // 当前生效配置内容的 sha256
+
+	Overrides []string `json:"overrides,omitempty"` // 当前版本命中的 overrides id
+}
+
+// History 返回保留的配置版本（新版本在前）
+func (m *Manager) History() []ConfigVersion {
+	return m.history.list()
+}
+
+// GetVersion 返回指定版本，包含配置内容
+func (m *Manager) GetVersion(version int64) (ConfigVersion, error) {
+	return m.history.get(version)
+}
+
+// CurrentVersion 返回当前版本状态
+func (m *Manager) CurrentVersion() VersionStatus {
+	current, pinned, remote := m.history.state()
+	snap := m.Snapshot()
+	status := VersionStatus{Current: current, Pending: m.pending(), Hash: snap.Hash(), Overrides: snap.Overrides()}
+	if remote != nil {
+		status.Remote = remote.Version
+	}
+	if pinned != nil {
+		status.Pinned = pinned.Version
+	}
+	return status
+}
+
+// Rollback 回滚到历史版本，配置来源下一次推送时会被覆盖；需要保持旧版本时使用 Pin
+func (m *Manager) Rollback(version int64) (ConfigVersion, error) {
+	defer m.notifyChangeListeners()
+	m.applyMu.Lock()
+	defer m.applyMu.Unlock()
+
+	if _, pinned, _ := m.history.state(); pinned != nil {
+		return ConfigVersion{}, fmt.Errorf("config is pinned to v%d, unpin first", pinned.Version)
+	}
+	v, err := m.applyVersion(version, SourceRollback)
+	if err != nil {
+		return ConfigVersion{}, err
+	}
+	logger.Warnf("App config rolled back to v%d as v%d (hash=%s)", version, v.Version, shortHash(v.Hash))
+	return v, nil
+}
+
+// Pin 应用历史版本并固定，之后忽略配置来源的推送直到 Unpin，重启后仍然生效
+func (m *Manager) Pin(version int64) (ConfigVersion, error) {
+	defer m.notifyChangeListeners()
+	m.applyMu.Lock()
+	defer m.applyMu.Unlock()
+
+	v, err := m.applyVersion(version, SourcePin)
+	if err != nil {
+		return ConfigVersion{}, err
+	}
+	if err := m.history.pin(&v); err != nil {
+		logger.Errorf("Failed to persist pinned config v%d: %v", v.Version, err)
+	}
+	logger.Warnf("App config pinned to v%d as v%d (hash=%s), updates are ignored until unpinned",
+		version, v.Version, shortHash(v.Hash))
+	return v, nil
+}
+
+// Unpin 取消固定，恢复配置来源最近一次推送的配置
+func (m *Manager) Unpin() (ConfigVersion, error) {
+	defer m.notifyChangeListeners()
+
+	// 配置来源上的配置先在锁外校验，试连期间不阻塞其他操作
+	_, _, remote := m.history.state()
+	tree, base, err := m.validateRemote(remote)
+
+	m.applyMu.Lock()
+	defer m.applyMu.Unlock()
+
+	_, pinned, latest := m.history.state()
+	if pinned == nil {
+		return ConfigVersion{}, fmt.Errorf("config is not pinned")
+	}
+	if latest == nil {
+		if err := m.history.pin(nil); err != nil {
+			return ConfigVersion{}, fmt.Errorf("failed to remove pinned config: %w", err)
+		}
+		logger.Warnf("App config unpinned, no config received yet, keeping v%d", pinned.Version)
+		return *pinned, nil
+	}
+	if remote == nil || remote.Version != latest.Version || m.tree.Load() != base {
+		// 校验期间收到了新的推送或配置发生了变化，重新校验
+		remote = latest
+		tree, _, err = m.validateRemote(remote)
+	}
+
+	// 配置来源上的配置仍然无效时保持固定
+	if err != nil {
+		return ConfigVersion{}, err
+	}
+	if err := m.history.pin(nil); err != nil {
+		return ConfigVersion{}, fmt.Errorf("failed to remove pinned config: %w", err)
+	}
+	v := m.history.record(remote.Content, SourceUnpin, true)
+	m.applyConfig(tree, v)
+	logger.Warnf("App config unpinned, restored config v%d as v%d (hash=%s)", remote.Version, v.Version, shortHash(v.Hash))
+	return v, nil
+}
+
+// validateRemote 解析并校验配置来源推送的版本，返回配置树和校验时的当前配置树
+func (m *Manager) validateRemote(remote *ConfigVersion) (*configTree, *configTree, error) {
+	if remote == nil {
+		return nil, nil, nil
+	}
+	tree, err := m.parseConfig(remote.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	base := m.tree.Load()
+	if err := m.validateAgainst(tree, base); err != nil {
+		return nil, nil, fmt.Errorf("latest config v%d is invalid, still pinned: %w", remote.Version, err)
+	}
+	return tree, base, nil
+}
+
+// applyVersion 重新应用历史版本，记录为新版本；运维操作不经过校验器，并取消等待中的灰度配置
+// 调用方持有 applyMu
+func (m *Manager) applyVersion(version int64, kind string) (ConfigVersion, error) {
+	target, err := m.history.get(version)
+	if err != nil {
+		return ConfigVersion{}, err
+	}
+	m.cancelPending()
+	tree, err := m.parseConfig(target.Content)
+	if err != nil {
+		return ConfigVersion{}, err
+	}
+	v := m.history.record(target.Content, sourceOf(kind, version), true)
+	m.applyConfig(tree, v)
+	return v, nil
+}
+
+// History 返回默认管理器保留的配置版本（新版本在前）
+func History() []ConfigVersion {
+	return defaultManager.History()
+}
+
+// GetVersion 返回默认管理器的指定版本，包含配置内容
+func GetVersion(version int64) (ConfigVersion, error) {
+	return defaultManager.GetVersion(version)
+}
+
+// CurrentVersion 返回默认管理器的版本状态
+func CurrentVersion() VersionStatus {
+	return defaultManager.CurrentVersion()
+}
+
+// Rollback 默认管理器回滚到历史版本
+func Rollback(version int64) (ConfigVersion, error) {
+	return defaultManager.Rollback(version)
+}
+
+// Pin 默认管理器固定到历史版本
+func Pin(version int64) (ConfigVersion, error) {
+	return defaultManager.Pin(version)
+}
+
+// Unpin 默认管理器取消固定
+func Unpin() (ConfigVersion, error) {
+	return defaultManager.Unpin()
+}
@@ -0,0 +1,37 @@
+package flags
+
+import (
+	"context"
+
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+)
+
+// FilterKey provider 开关 filter 名称，通过 server.WithServerFilter 启用
+const FilterKey = "flags"
+
+// 请求属性中 service 和 method 的名称
+const (
+	AttrService = "service"
+	AttrMethod  = "method"
+)
+
+func init() {
+	extension.SetFilter(FilterKey, func() filter.Filter { return &flagFilter{} })
+}
+
+type flagFilter struct{}
+
+// Invoke 把调用方、用户 ID 和请求属性放入 context，业务代码中的 flags.Bool 等方法按此求值
+// 请求属性在第一次求值时才从 attachment 构建，没有读取开关的请求不复制 attachment
+func (f *flagFilter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	ctx = context.WithValue(ctx, targetCtxKey{}, &invocationTarget{invoker: invoker, invocation: invocation})
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse 直接返回结果
+func (f *flagFilter) OnResponse(_ context.Context, result result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return result
+}
@@ -0,0 +1,246 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync/atomic"
+
+	"helloworld/pkg/config"
+	"helloworld/pkg/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// logger 开关日志，每次求值输出一条 debug 日志，通过 log.levels 中的 helloworld/pkg/flags 开启
+var logger = log.Named("helloworld/pkg/flags")
+
+// evaluations 开关求值次数，通过管理端 /metrics 暴露
+var evaluations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "helloworld",
+	Subsystem: "flags",
+	Name:      "evaluations_total",
+	Help:      "Feature flag evaluations by flag, value and reason.",
+}, []string{"flag", "value", "reason"})
+
+// 求值原因
+const (
+	ReasonRule     = "rule"     // 命中规则
+	ReasonDefault  = "default"  // 没有命中规则，取开关的 default
+	ReasonMissing  = "missing"  // 开关未定义，取代码中的默认值
+	ReasonMismatch = "mismatch" // 开关类型与读取方法不一致，取代码中的默认值
+)
+
+// current 当前的开关定义，热更新时整体替换，求值时无锁读取
+var current atomic.Pointer[flagSet]
+
+// flagSet 一个配置版本的全部开关，发布后只读
+type flagSet struct {
+	flags map[string]*flag
+}
+
+// flag 单个开关
+type flag struct {
+	cfg      config.FlagConfig
+	counters map[counterKey]prometheus.Counter // 预先创建的指标，避免求值时查找 CounterVec
+	warned   atomic.Bool                       // 已输出过类型不一致的警告，配置更新后重新加载时重置
+}
+
+type counterKey struct {
+	value  string
+	reason string
+}
+
+// Evaluation 一次求值的结果
+type Evaluation struct {
+	Flag   string
+	Value  interface{} // bool 或 string，开关未定义时为 nil
+	Reason string
+	Rule   int // 命中的规则下标，未命中规则时为 -1
+}
+
+// Setup 加载开关定义并监听配置变化
+func Setup() {
+	reload()
+	config.RegisterChangeListener(func(map[string]interface{}) {
+		reload()
+	})
+}
+
+// reload 重新加载开关定义
+func reload() {
+	install(config.GetFlagsConfigFromDubbo())
+}
+
+// install 发布一组开关定义，替换当前的全部开关
+func install(cfgs map[string]config.FlagConfig) {
+	set := &flagSet{flags: make(map[string]*flag, len(cfgs))}
+	for name, cfg := range cfgs {
+		f := &flag{cfg: cfg, counters: make(map[counterKey]prometheus.Counter)}
+		values := []interface{}{cfg.Default}
+		for _, rule := range cfg.Rules {
+			values = append(values, rule.Value)
+		}
+		for _, v := range values {
+			for _, reason := range []string{ReasonRule, ReasonDefault} {
+				key := counterKey{value: formatValue(v), reason: reason}
+				f.counters[key] = evaluations.WithLabelValues(name, key.value, reason)
+			}
+		}
+		set.flags[name] = f
+	}
+	current.Store(set)
+	logger.Infof("Feature flags loaded: %d flag(s)", len(set.flags))
+}
+
+// Names 当前定义的开关名称
+func Names() []string {
+	set := current.Load()
+	if set == nil {
+		return nil
+	}
+	names := make([]string, 0, len(set.flags))
+	for name := range set.flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Evaluate 按 ctx 中的调用目标对开关求值，记录指标和 debug 日志
+func Evaluate(ctx context.Context, name string) Evaluation {
+	f, e := evaluate(ctx, name)
+	return record(ctx, f, e)
+}
+
+// Bool 读取 bool 开关，开关未定义或不是 bool 类型时返回 def
+func Bool(ctx context.Context, name string, def bool) bool {
+	f, e := evaluate(ctx, name)
+	v, ok := e.Value.(bool)
+	if !ok {
+		v = withDefault(ctx, f, &e, def)
+	}
+	record(ctx, f, e)
+	return v
+}
+
+// String 读取 string 开关，开关未定义或不是 string 类型时返回 def
+func String(ctx context.Context, name string, def string) string {
+	f, e := evaluate(ctx, name)
+	v, ok := e.Value.(string)
+	if !ok {
+		v = withDefault(ctx, f, &e, def)
+	}
+	record(ctx, f, e)
+	return v
+}
+
+// evaluate 求值，不记录指标和日志
+func evaluate(ctx context.Context, name string) (*flag, Evaluation) {
+	var f *flag
+	if set := current.Load(); set != nil {
+		f = set.flags[name]
+	}
+	if f == nil {
+		return nil, Evaluation{Flag: name, Reason: ReasonMissing, Rule: -1}
+	}
+	target := TargetFromContext(ctx)
+	for i, rule := range f.cfg.Rules {
+		if matches(name, rule, target) {
+			return f, Evaluation{Flag: name, Value: rule.Value, Reason: ReasonRule, Rule: i}
+		}
+	}
+	return f, Evaluation{Flag: name, Value: f.cfg.Default, Reason: ReasonDefault, Rule: -1}
+}
+
+// withDefault 开关未定义或类型与读取方法不一致时改为代码中的默认值
+// 类型不一致通常是代码和配置中的开关类型不同，每个配置版本中每个开关只警告一次，之后通过 mismatch 指标观察
+func withDefault[T bool | string](ctx context.Context, f *flag, e *Evaluation, def T) T {
+	if e.Value != nil {
+		if f.warned.CompareAndSwap(false, true) {
+			log.FromContext(ctx).Warnf("flags: %s is not a %T flag, using default %v", e.Flag, def, def)
+		}
+		e.Reason = ReasonMismatch
+		e.Rule = -1
+	}
+	e.Value = def
+	return def
+}
+
+// matches 判断调用目标是否满足规则
+func matches(name string, rule config.FlagRule, t Target) bool {
+	if len(rule.Callers) > 0 {
+		found := false
+		for _, caller := range rule.Callers {
+			if caller == t.CallerApp {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for k, v := range rule.Attributes {
+		if t.Attributes[k] != v {
+			return false
+		}
+	}
+	if rule.Percent != nil && (t.UserID == "" || userBucket(name, t.UserID) >= *rule.Percent) {
+		return false
+	}
+	return true
+}
+
+// userBucket 用户在某个开关下的哈希桶，范围 [0, 100)，精度 0.01
+// 同一用户在同一开关下结果稳定，比例调大时已命中的用户保持命中
+func userBucket(name, userID string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(name + "/" + userID))
+	return float64(h.Sum32()%10000) / 100
+}
+
+// record 记录求值指标和 debug 日志
+func record(ctx context.Context, f *flag, e Evaluation) Evaluation {
+	value := formatValue(e.Value)
+	if c, ok := f.counter(value, e.Reason); ok {
+		c.Inc()
+	} else {
+		evaluations.WithLabelValues(e.Flag, value, e.Reason).Inc()
+	}
+	if ce := logger.Desugar().Check(zap.DebugLevel, "flag evaluated"); ce != nil {
+		ce.Write(append(log.Fields(ctx),
+			zap.String("flag", e.Flag),
+			zap.String("value", value),
+			zap.String("reason", e.Reason),
+			zap.Int("rule", e.Rule),
+		)...)
+	}
+	return e
+}
+
+// counter 预先创建的指标
+func (f *flag) counter(value, reason string) (prometheus.Counter, bool) {
+	if f == nil {
+		return nil, false
+	}
+	c, ok := f.counters[counterKey{value: value, reason: reason}]
+	return c, ok
+}
+
+// formatValue 指标和日志中的取值
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(val)
+	case string:
+		return val
+	}
+	return fmt.Sprint(v)
+}
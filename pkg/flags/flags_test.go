@@ -0,0 +1,251 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"helloworld/pkg/config"
+	"helloworld/pkg/rpcctx"
+
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const testFlags = `
+flags:
+  new-greeting:
+    type: bool
+    default: false
+    rules:
+      - callers: [go-client]
+        value: true
+      - attributes: {region: hz}
+        value: "on"
+  greeting-style:
+    type: string
+    variants: [plain, fancy, emoji]
+    default: plain
+    rules:
+      - callers: [vip-app]
+        attributes: {tier: gold}
+        value: emoji
+      - callers: [other-app, vip-app]
+        value: fancy
+      - percent: 50
+        value: fancy
+  broken:
+    type: string
+    variants: [a, b]
+    default: c
+`
+
+// installFlags 通过独立的配置管理器加载开关定义，测试结束后清空
+func installFlags(t *testing.T, content string) {
+	t.Helper()
+	m := config.NewManager(config.NewMemorySource(content), config.WithoutDefaultValidators())
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	install(m.GetFlagsConfig())
+	t.Cleanup(func() { current.Store(nil) })
+}
+
+// userIn 找到一个在开关比例内或比例外的用户 ID
+func userIn(t *testing.T, name string, percent float64, inside bool) string {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("user-%d", i)
+		if (userBucket(name, id) < percent) == inside {
+			return id
+		}
+	}
+	t.Fatalf("no user found for %s inside=%v", name, inside)
+	return ""
+}
+
+func TestEvaluate(t *testing.T) {
+	installFlags(t, testFlags)
+	inside := userIn(t, "greeting-style", 50, true)
+	outside := userIn(t, "greeting-style", 50, false)
+
+	tests := []struct {
+		name   string
+		flag   string
+		target Target
+		value  interface{}
+		reason string
+		rule   int
+	}{
+		{name: "caller rule", flag: "new-greeting", target: Target{CallerApp: "go-client"}, value: true, reason: ReasonRule, rule: 0},
+		{name: "attribute rule", flag: "new-greeting", target: Target{Attributes: map[string]string{"region": "hz"}}, value: true, reason: ReasonRule, rule: 1},
+		{name: "attribute differs", flag: "new-greeting", target: Target{Attributes: map[string]string{"region": "sh"}}, value: false, reason: ReasonDefault, rule: -1},
+		{name: "no target", flag: "new-greeting", value: false, reason: ReasonDefault, rule: -1},
+		{
+			name:   "first matching rule wins",
+			flag:   "greeting-style",
+			target: Target{CallerApp: "vip-app", UserID: inside, Attributes: map[string]string{"tier": "gold"}},
+			value:  "emoji", reason: ReasonRule, rule: 0,
+		},
+		{
+			name:   "all conditions of a rule are required",
+			flag:   "greeting-style",
+			target: Target{CallerApp: "go-client", Attributes: map[string]string{"tier": "gold"}},
+			value:  "plain", reason: ReasonDefault, rule: -1,
+		},
+		{name: "any caller in list", flag: "greeting-style", target: Target{CallerApp: "vip-app"}, value: "fancy", reason: ReasonRule, rule: 1},
+		{name: "percent inside", flag: "greeting-style", target: Target{UserID: inside}, value: "fancy", reason: ReasonRule, rule: 2},
+		{name: "percent outside", flag: "greeting-style", target: Target{UserID: outside}, value: "plain", reason: ReasonDefault, rule: -1},
+		{name: "percent without user", flag: "greeting-style", target: Target{CallerApp: "go-client"}, value: "plain", reason: ReasonDefault, rule: -1},
+		{name: "missing", flag: "no-such-flag", target: Target{CallerApp: "go-client"}, value: nil, reason: ReasonMissing, rule: -1},
+		{name: "invalid flag is skipped", flag: "broken", value: nil, reason: ReasonMissing, rule: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Evaluate(WithTarget(context.Background(), tt.target), tt.flag)
+			if e.Value != tt.value || e.Reason != tt.reason || e.Rule != tt.rule {
+				t.Errorf("Evaluate() = %+v, want value=%v reason=%s rule=%d", e, tt.value, tt.reason, tt.rule)
+			}
+		})
+	}
+}
+
+func TestTypedGetters(t *testing.T) {
+	installFlags(t, testFlags)
+	ctx := WithTarget(context.Background(), Target{CallerApp: "go-client"})
+
+	if !Bool(ctx, "new-greeting", false) {
+		t.Error("Bool(new-greeting) = false, want true from rule")
+	}
+	if Bool(context.Background(), "new-greeting", true) {
+		t.Error("Bool(new-greeting) = true, want false from default")
+	}
+	if !Bool(ctx, "no-such-flag", true) {
+		t.Error("Bool(no-such-flag) = false, want code default true")
+	}
+	if got := String(ctx, "greeting-style", "x"); got != "plain" {
+		t.Errorf("String(greeting-style) = %q, want plain", got)
+	}
+	if got := String(ctx, "no-such-flag", "x"); got != "x" {
+		t.Errorf("String(no-such-flag) = %q, want x", got)
+	}
+}
+
+func TestMismatchFallsBackToDefault(t *testing.T) {
+	installFlags(t, testFlags)
+	ctx := WithTarget(context.Background(), Target{CallerApp: "go-client"})
+
+	f, e := evaluate(ctx, "new-greeting")
+	if e.Reason != ReasonRule {
+		t.Fatalf("reason = %s, want rule", e.Reason)
+	}
+	if got := withDefault(ctx, f, &e, "fallback"); got != "fallback" {
+		t.Errorf("withDefault() = %q, want fallback", got)
+	}
+	if e.Value != "fallback" || e.Reason != ReasonMismatch || e.Rule != -1 {
+		t.Errorf("evaluation = %+v, want fallback/mismatch/-1", e)
+	}
+	if !f.warned.Load() {
+		t.Error("mismatch was not marked as warned")
+	}
+
+	// 未定义的开关不是类型不一致
+	f, e = evaluate(ctx, "no-such-flag")
+	withDefault(ctx, f, &e, true)
+	if e.Reason != ReasonMissing || e.Value != true {
+		t.Errorf("evaluation = %+v, want true/missing", e)
+	}
+
+	mismatch := evaluations.WithLabelValues("greeting-style", "true", ReasonMismatch)
+	before := testutil.ToFloat64(mismatch)
+	if !Bool(ctx, "greeting-style", true) {
+		t.Error("Bool(greeting-style) = false, want code default true")
+	}
+	if got := testutil.ToFloat64(mismatch) - before; got != 1 {
+		t.Errorf("mismatch evaluations = %v, want 1", got)
+	}
+}
+
+func TestPercentRolloutIsMonotonic(t *testing.T) {
+	prev := make(map[string]bool)
+	for _, p := range []float64{0, 10, 30, 60, 100} {
+		rule := config.FlagRule{Percent: &p, Value: true}
+		for i := 0; i < 500; i++ {
+			id := fmt.Sprintf("user-%d", i)
+			hit := matches("rollout", rule, Target{UserID: id})
+			if prev[id] && !hit {
+				t.Fatalf("user %s matched at a lower percent but not at %v", id, p)
+			}
+			if p == 0 && hit || p == 100 && !hit {
+				t.Fatalf("user %s hit=%v at percent %v", id, hit, p)
+			}
+			prev[id] = hit
+		}
+	}
+}
+
+func TestReloadReplacesFlags(t *testing.T) {
+	installFlags(t, testFlags)
+	if got := Names(); len(got) != 2 {
+		t.Fatalf("Names() = %v, want 2 valid flags", got)
+	}
+	installFlags(t, "flags:\n  only:\n    type: bool\n    default: true\n")
+	if got := Names(); len(got) != 1 || got[0] != "only" {
+		t.Errorf("Names() = %v, want [only]", got)
+	}
+	if e := Evaluate(context.Background(), "new-greeting"); e.Reason != ReasonMissing {
+		t.Errorf("removed flag reason = %s, want missing", e.Reason)
+	}
+}
+
+// evalInvoker 在调用中读取开关
+type evalInvoker struct {
+	*base.BaseInvoker
+	eval func(ctx context.Context)
+}
+
+func (i *evalInvoker) Invoke(ctx context.Context, _ base.Invocation) result.Result {
+	i.eval(ctx)
+	return &result.RPCResult{}
+}
+
+func TestFilterBuildsTargetOnFirstEvaluation(t *testing.T) {
+	installFlags(t, testFlags)
+	url, err := common.NewURL("tri://127.0.0.1:20000/greet.GreetService?interface=greet.GreetService")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv := invocation.NewRPCInvocation("Greet", nil, map[string]interface{}{
+		rpcctx.CallerAppKey: "vip-app",
+		"tier":              "gold",
+	})
+
+	var (
+		builtBefore, builtAfter bool
+		style                   string
+		target                  Target
+	)
+	invoker := &evalInvoker{BaseInvoker: base.NewBaseInvoker(url), eval: func(ctx context.Context) {
+		it := ctx.Value(targetCtxKey{}).(*invocationTarget)
+		builtBefore = it.invocation == nil
+		style = String(ctx, "greeting-style", "x")
+		target = TargetFromContext(ctx)
+		builtAfter = it.invocation == nil
+	}}
+	(&flagFilter{}).Invoke(context.Background(), invoker, inv)
+
+	if builtBefore || !builtAfter {
+		t.Errorf("target built before evaluation = %v, after = %v, want false and true", builtBefore, builtAfter)
+	}
+	if style != "emoji" {
+		t.Errorf("String(greeting-style) = %q, want emoji", style)
+	}
+	want := map[string]string{rpcctx.CallerAppKey: "vip-app", "tier": "gold", AttrService: "greet.GreetService", AttrMethod: "Greet"}
+	if target.CallerApp != "vip-app" || !reflect.DeepEqual(target.Attributes, want) {
+		t.Errorf("TargetFromContext() = %+v, want attributes %v", target, want)
+	}
+}
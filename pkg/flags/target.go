@@ -0,0 +1,72 @@
+package flags
+
+import (
+	"context"
+	"sync"
+
+	"helloworld/pkg/rpcctx"
+
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+)
+
+// Target 开关求值的调用目标
+type Target struct {
+	CallerApp  string            // 调用方应用名
+	UserID     string            // 用户 ID，按比例灰度的哈希依据
+	Attributes map[string]string // 请求属性：attachment 以及 service、method
+}
+
+// targetCtxKey 在 context 中保存调用目标
+type targetCtxKey struct{}
+
+// WithTarget 返回携带调用目标的 context，provider filter 会为每个请求设置，业务代码也可以自行设置（如异步任务）
+func WithTarget(ctx context.Context, t Target) context.Context {
+	return context.WithValue(ctx, targetCtxKey{}, t)
+}
+
+// TargetFromContext 获取调用目标，没有通过 WithTarget 设置时从 Triple attachment 中读取调用方和用户 ID
+func TargetFromContext(ctx context.Context) Target {
+	if ctx == nil {
+		return Target{}
+	}
+	switch t := ctx.Value(targetCtxKey{}).(type) {
+	case Target:
+		return t
+	case *invocationTarget:
+		return t.get()
+	}
+	return Target{
+		CallerApp: rpcctx.CallerApp(ctx),
+		UserID:    rpcctx.Attachment(ctx, rpcctx.UserIDKey),
+	}
+}
+
+// invocationTarget provider filter 放入 context 的调用目标，第一次求值时构建，同一请求中的后续求值复用
+type invocationTarget struct {
+	once       sync.Once
+	invoker    base.Invoker
+	invocation base.Invocation
+	target     Target
+}
+
+// get 构建调用目标：attachment 以及 service、method
+func (it *invocationTarget) get() Target {
+	it.once.Do(func() {
+		attachments := it.invocation.Attachments()
+		attrs := make(map[string]string, len(attachments)+2)
+		for k := range attachments {
+			if v := rpcctx.InvocationAttachment(it.invocation, k); v != "" {
+				attrs[k] = v
+			}
+		}
+		attrs[AttrService] = it.invoker.GetURL().Service()
+		attrs[AttrMethod] = it.invocation.MethodName()
+		it.target = Target{
+			CallerApp:  attrs[rpcctx.CallerAppKey],
+			UserID:     attrs[rpcctx.UserIDKey],
+			Attributes: attrs,
+		}
+		it.invoker, it.invocation = nil, nil
+	})
+	return it.target
+}
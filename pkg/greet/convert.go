@@ -0,0 +1,71 @@
+package greet
+
+import (
+	"time"
+
+	greetpb "helloworld/greet"
+)
+
+// ToProto 转换为 RPC 返回的问候记录
+func (g *Greeting) ToProto() *greetpb.Greeting {
+	return &greetpb.Greeting{
+		Id:        g.ID,
+		Name:      g.Name,
+		CallerApp: g.CallerApp,
+		TraceId:   g.TraceID,
+		CreatedAt: toUnixMilli(g.CreatedAt),
+	}
+}
+
+// ToProto 转换为 RPC 返回的统计结果
+func (s *Stats) ToProto() *greetpb.GetGreetingStatsResponse {
+	resp := &greetpb.GetGreetingStatsResponse{
+		Total:          s.Total,
+		UniqueNames:    s.UniqueNames,
+		FirstGreetedAt: toUnixMilli(s.FirstAt),
+		LastGreetedAt:  toUnixMilli(s.LastAt),
+		TopNames:       make([]*greetpb.NameCount, 0, len(s.TopNames)),
+	}
+	for _, nc := range s.TopNames {
+		resp.TopNames = append(resp.TopNames, &greetpb.NameCount{Name: nc.Name, Count: nc.Count})
+	}
+	return resp
+}
+
+// FilterFromList 从 ListGreetings 请求构建查询条件（时间为 unix 毫秒）
+func FilterFromList(req *greetpb.ListGreetingsRequest) ListFilter {
+	filter := ListFilter{
+		Name:     req.GetName(),
+		Start:    fromUnixMilli(req.GetStartTime()),
+		End:      fromUnixMilli(req.GetEndTime()),
+		Page:     int(req.GetPage()),
+		PageSize: int(req.GetPageSize()),
+	}
+	filter.Normalize()
+	return filter
+}
+
+// FilterFromStats 从 GetGreetingStats 请求构建查询条件（时间为 unix 毫秒）
+func FilterFromStats(req *greetpb.GetGreetingStatsRequest) ListFilter {
+	return ListFilter{
+		Name:  req.GetName(),
+		Start: fromUnixMilli(req.GetStartTime()),
+		End:   fromUnixMilli(req.GetEndTime()),
+	}
+}
+
+// toUnixMilli 零值时间返回 0
+func toUnixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// fromUnixMilli 0 返回零值时间
+func fromUnixMilli(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,39 @@
+package greet
+
+import (
+	"helloworld/pkg/migrate"
+
+	"gorm.io/gorm"
+)
+
+// Migrations 返回 greet 模块的 schema 迁移
+// 已发布的迁移不允许修改结果，新的变更请追加新版本；DDL 不能回滚，迁移需要可以重复执行
+func Migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version: 202610010001,
+			Name:    "create greetings table",
+			Up: func(tx *gorm.DB) error {
+				return tx.Exec(`CREATE TABLE IF NOT EXISTS greetings (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	name VARCHAR(128) NOT NULL,
+	caller_app VARCHAR(128) NOT NULL DEFAULT '',
+	trace_id VARCHAR(64) NOT NULL DEFAULT '',
+	created_at DATETIME(3) NOT NULL,
+	PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`).Error
+			},
+		},
+		{
+			Version: 202610010002,
+			Name:    "add greetings name/created_at indexes",
+			// 每个索引单独检查后创建，部分索引已建好时重试不会失败
+			Up: func(tx *gorm.DB) error {
+				if err := migrate.AddIndex(tx, "greetings", "idx_greetings_name_created_at", "name, created_at"); err != nil {
+					return err
+				}
+				return migrate.AddIndex(tx, "greetings", "idx_greetings_created_at", "created_at")
+			},
+		},
+	}
+}
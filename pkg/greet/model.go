@@ -0,0 +1,61 @@
+package greet
+
+import "time"
+
+// Greeting 一次问候记录
+type Greeting struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	Name      string    `gorm:"type:varchar(128);not null"`
+	CallerApp string    `gorm:"type:varchar(128);not null;default:''"`
+	TraceID   string    `gorm:"type:varchar(64);not null;default:''"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (Greeting) TableName() string {
+	return "greetings"
+}
+
+// ListFilter 问候记录查询条件
+type ListFilter struct {
+	Name     string    // 按名字精确过滤，为空表示不过滤
+	Start    time.Time // 起始时间（含），零值表示不限
+	End      time.Time // 结束时间（不含），零值表示不限
+	Page     int       // 页码，从 1 开始
+	PageSize int       // 每页条数
+}
+
+// NameCount 按名字聚合的问候次数
+type NameCount struct {
+	Name  string
+	Count int64
+}
+
+// Stats 问候统计
+type Stats struct {
+	Total       int64
+	UniqueNames int64
+	FirstAt     time.Time
+	LastAt      time.Time
+	TopNames    []NameCount
+}
+
+// 分页默认值
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+	topNamesLimit   = 10
+)
+
+// Normalize 修正分页参数
+func (f *ListFilter) Normalize() {
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+	if f.PageSize <= 0 {
+		f.PageSize = DefaultPageSize
+	}
+	if f.PageSize > MaxPageSize {
+		f.PageSize = MaxPageSize
+	}
+}
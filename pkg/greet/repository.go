@@ -0,0 +1,127 @@
+package greet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Repository 问候记录仓储
+type Repository interface {
+	// Create 保存一条问候记录
+	Create(ctx context.Context, g *Greeting) error
+	// List 分页查询问候记录，返回当前页数据和总数
+	List(ctx context.Context, filter ListFilter) ([]Greeting, int64, error)
+	// Stats 统计问候记录（忽略分页参数）
+	Stats(ctx context.Context, filter ListFilter) (*Stats, error)
+}
+
+// gormRepository 基于 GORM 的仓储实现
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository 创建基于 GORM 的问候记录仓储
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// Create 保存一条问候记录
+func (r *gormRepository) Create(ctx context.Context, g *Greeting) error {
+	if g.CreatedAt.IsZero() {
+		g.CreatedAt = time.Now()
+	}
+	if err := r.db.WithContext(ctx).Create(g).Error; err != nil {
+		return fmt.Errorf("failed to create greeting: %w", err)
+	}
+	return nil
+}
+
+// List 分页查询问候记录
+func (r *gormRepository) List(ctx context.Context, filter ListFilter) ([]Greeting, int64, error) {
+	filter.Normalize()
+
+	query := r.scope(ctx, filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count greetings: %w", err)
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	var greetings []Greeting
+	err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&greetings).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list greetings: %w", err)
+	}
+
+	return greetings, total, nil
+}
+
+// Stats 统计问候记录
+func (r *gormRepository) Stats(ctx context.Context, filter ListFilter) (*Stats, error) {
+	var row struct {
+		Total       int64
+		UniqueNames int64
+		FirstAt     *time.Time
+		LastAt      *time.Time
+	}
+	err := r.scope(ctx, filter).
+		Select("COUNT(*) AS total, COUNT(DISTINCT name) AS unique_names, " +
+			"MIN(created_at) AS first_at, MAX(created_at) AS last_at").
+		Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat greetings: %w", err)
+	}
+
+	stats := &Stats{
+		Total:       row.Total,
+		UniqueNames: row.UniqueNames,
+	}
+	if row.FirstAt != nil {
+		stats.FirstAt = *row.FirstAt
+	}
+	if row.LastAt != nil {
+		stats.LastAt = *row.LastAt
+	}
+	if stats.Total == 0 {
+		return stats, nil
+	}
+
+	err = r.scope(ctx, filter).
+		Select("name, COUNT(*) AS count").
+		Group("name").
+		Order("count DESC").
+		Order("name").
+		Limit(topNamesLimit).
+		Scan(&stats.TopNames).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat greeting names: %w", err)
+	}
+
+	return stats, nil
+}
+
+// scope 根据过滤条件构建查询
+func (r *gormRepository) scope(ctx context.Context, filter ListFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&Greeting{})
+	if filter.Name != "" {
+		query = query.Where("name = ?", filter.Name)
+	}
+	if !filter.Start.IsZero() {
+		query = query.Where("created_at >= ?", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		query = query.Where("created_at < ?", filter.End)
+	}
+	return query
+}
@@ -0,0 +1,309 @@
+// Package idempotency 基于 Redis 的请求幂等 provider filter：客户端通过 idempotency-key attachment 传递幂等键，
+// 首次成功的响应连同请求指纹一起保存，相同键的重复请求回放响应，不再执行 handler。
+//
+// 只有携带幂等键的请求才会处理，客户端对有副作用的调用（如 Greet 落库）设置幂等键即可，handler 不需要改动。
+// 响应以 protobuf Any 保存，回放时按类型名解码，只支持 IDL 模式（请求和响应都是 protobuf 消息）。
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"helloworld/pkg/lock"
+	"helloworld/pkg/log"
+	"helloworld/pkg/rpcctx"
+
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// FilterKey provider 幂等 filter 名称，通过 server.WithServerFilter 启用
+const FilterKey = "idempotency"
+
+// AttachmentKey 客户端通过该 attachment 传递幂等键
+const AttachmentKey = "idempotency-key"
+
+// maxKeyLength 幂等键最大长度
+const maxKeyLength = 128
+
+// ErrInProgress 相同幂等键的请求正在处理中
+var ErrInProgress = triple_protocol.NewError(triple_protocol.CodeAborted,
+	errors.New("request with the same idempotency key is in progress"))
+
+// ErrConflict 幂等键已用于内容不同的请求，通常是客户端复用了幂等键
+var ErrConflict = triple_protocol.NewError(triple_protocol.CodeInvalidArgument,
+	errors.New("idempotency key was already used with a different request"))
+
+// fingerprintSize 保存的响应前的请求指纹长度（sha256）
+const fingerprintSize = sha256.Size
+
+// Options 幂等配置
+type Options struct {
+	Prefix  string        // key 前缀
+	TTL     time.Duration // 响应保存时间，默认 24h
+	LockTTL time.Duration // 处理中锁的过期时间，默认 30s，开启看门狗自动续期
+	Timeout time.Duration // Redis 单次操作超时，默认 200ms
+}
+
+// Store 基于 Redis 的幂等存储
+type Store struct {
+	client *redis.Client
+	locker *lock.Locker
+	opts   Options
+}
+
+// New 创建幂等存储，client 为 nil 时不做幂等处理
+func New(client *redis.Client, opts Options) *Store {
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = 30 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 200 * time.Millisecond
+	}
+	return &Store{
+		client: client,
+		locker: lock.NewLocker(client, opts.Prefix+"lock:"),
+		opts:   opts,
+	}
+}
+
+// store filter 使用的幂等存储，Setup 之前不做幂等处理
+var store atomic.Pointer[Store]
+
+func init() {
+	extension.SetFilter(FilterKey, newFilter)
+}
+
+// Setup 设置 filter 使用的 Redis，client 为 nil 时不做幂等处理
+func Setup(client *redis.Client, opts Options) {
+	store.Store(New(client, opts))
+}
+
+type idempotencyFilter struct{}
+
+func newFilter() filter.Filter {
+	return &idempotencyFilter{}
+}
+
+// Invoke 请求携带幂等键时以幂等方式执行，见 Store.invoke
+func (f *idempotencyFilter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	idemKey := rpcctx.InvocationAttachment(invocation, AttachmentKey)
+	s := store.Load()
+	if idemKey == "" || s == nil || s.client == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	method := invoker.GetURL().Service() + "." + invocation.MethodName()
+	caller := rpcctx.InvocationAttachment(invocation, rpcctx.CallerAppKey)
+	return s.invoke(ctx, method, caller, idemKey, invocation.Arguments(), func(ctx context.Context) result.Result {
+		return invoker.Invoke(ctx, invocation)
+	})
+}
+
+// OnResponse 直接返回结果
+func (f *idempotencyFilter) OnResponse(_ context.Context, result result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return result
+}
+
+// invoke 以幂等方式执行 next，args 为请求参数，用于计算请求指纹：
+// 首次执行成功后保存响应和请求指纹，重复请求直接回放保存的响应；
+// 相同键但请求内容不同时返回 ErrConflict；相同键的请求正在执行时返回 ErrInProgress；
+// 执行失败不保存，允许客户端用相同键重试；执行期间锁已丢失时同样不保存。
+// 请求或响应不是 protobuf 消息、Redis 不可用时直接执行 next。
+func (s *Store) invoke(ctx context.Context, method, caller, idemKey string, args []interface{},
+	next func(context.Context) result.Result) result.Result {
+	if len(idemKey) > maxKeyLength {
+		return errorResult(triple_protocol.NewError(triple_protocol.CodeInvalidArgument,
+			fmt.Errorf("idempotency key exceeds %d bytes", maxKeyLength)))
+	}
+
+	// 按调用方和方法隔离幂等键
+	scope := scopeKey(method, caller, idemKey)
+
+	fp, err := fingerprint(args)
+	if err != nil {
+		log.FromContext(ctx).Warnf("idempotency: fingerprint request for %s failed, executing without idempotency: %v", scope, err)
+		return next(ctx)
+	}
+
+	// 响应与锁使用相同的 hash tag，Redis Cluster 下可以在一个脚本中检查锁并写入
+	respKey := s.opts.Prefix + "resp:{" + scope + "}"
+
+	// 1. 已有保存的响应，请求指纹一致时直接回放
+	if resp, ok, err := s.load(ctx, respKey, fp); err != nil {
+		if errors.Is(err, ErrConflict) {
+			log.FromContext(ctx).Warnf("idempotency: %s reused with a different request", scope)
+			return errorResult(err)
+		}
+		log.FromContext(ctx).Warnf("idempotency: load %s failed, executing without idempotency: %v", scope, err)
+		return next(ctx)
+	} else if ok {
+		log.FromContext(ctx).Infof("idempotency: replay response for %s", scope)
+		return replayResult(resp)
+	}
+
+	// 2. 加锁，防止并发的重复请求同时执行
+	lk, err := s.locker.Obtain(ctx, scope, lock.Options{TTL: s.opts.LockTTL, AutoRenew: true})
+	if errors.Is(err, lock.ErrNotObtained) {
+		return errorResult(ErrInProgress)
+	}
+	if err != nil {
+		log.FromContext(ctx).Warnf("idempotency: lock %s failed, executing without idempotency: %v", scope, err)
+		return next(ctx)
+	}
+	defer func() {
+		if err := lk.Release(context.WithoutCancel(ctx)); err != nil && !errors.Is(err, lock.ErrLockNotHeld) {
+			log.FromContext(ctx).Warnf("idempotency: release %s failed: %v", scope, err)
+		}
+	}()
+	// 3. 加锁后再检查一次，避免上一个持有者刚刚保存完响应
+	if resp, ok, err := s.load(ctx, respKey, fp); errors.Is(err, ErrConflict) {
+		log.FromContext(ctx).Warnf("idempotency: %s reused with a different request", scope)
+		return errorResult(err)
+	} else if err == nil && ok {
+		log.FromContext(ctx).Infof("idempotency: replay response for %s", scope)
+		return replayResult(resp)
+	}
+
+	// 4. 执行并保存响应
+	res := next(ctx)
+	if res.Error() != nil {
+		return res
+	}
+	msg, ok := responseMessage(res.Result())
+	if !ok {
+		log.FromContext(ctx).Warnf("idempotency: response of %s is %T, not a protobuf message, not saved", scope, res.Result())
+		return res
+	}
+	data, err := proto.Marshal(mustAny(msg))
+	if err != nil {
+		log.FromContext(ctx).Warnf("idempotency: encode response for %s failed: %v", scope, err)
+		return res
+	}
+	record := make([]byte, 0, fingerprintSize+len(data))
+	record = append(append(record, fp...), data...)
+
+	// 检查锁和写入原子执行：执行期间看门狗续期失败时锁可能已被其他请求取得，不能覆盖新持有者的结果
+	setCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.opts.Timeout)
+	defer cancel()
+	switch err := lk.SetIfHeld(setCtx, respKey, record, s.opts.TTL); {
+	case errors.Is(err, lock.ErrLockNotHeld):
+		log.FromContext(ctx).Errorf("idempotency: lock %s lost while executing, response not saved", scope)
+	case err != nil:
+		log.FromContext(ctx).Warnf("idempotency: save response for %s failed: %v", scope, err)
+	}
+	return res
+}
+
+// scopeKey 幂等键的作用域：方法、调用方和幂等键
+// 调用方和幂等键都由客户端控制，各部分带上长度前缀，避免 "a:b"+"c" 与 "a"+"b:c" 这类拼接冲突
+func scopeKey(method, caller, idemKey string) string {
+	return fmt.Sprintf("%d:%s:%d:%s:%s", len(method), method, len(caller), caller, idemKey)
+}
+
+// load 读取保存的响应，保存的请求指纹与 fp 不一致时返回 ErrConflict
+func (s *Store) load(ctx context.Context, key string, fp []byte) (proto.Message, bool, error) {
+	getCtx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+	data, err := s.client.Get(getCtx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(data) < fingerprintSize {
+		return nil, false, fmt.Errorf("saved response is too short")
+	}
+	if !bytes.Equal(data[:fingerprintSize], fp) {
+		return nil, false, ErrConflict
+	}
+	var saved anypb.Any
+	if err := proto.Unmarshal(data[fingerprintSize:], &saved); err != nil {
+		return nil, false, err
+	}
+	msg, err := saved.UnmarshalNew()
+	if err != nil {
+		return nil, false, err
+	}
+	return msg, true, nil
+}
+
+// fingerprint 请求指纹：各参数确定性序列化（带长度前缀）后的 sha256，同一请求多次序列化结果一致
+func fingerprint(args []interface{}) ([]byte, error) {
+	h := sha256.New()
+	var size [8]byte
+	for i, arg := range args {
+		m, ok := arg.(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("argument %d is %T, not a protobuf message", i, arg)
+		}
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint64(size[:], uint64(len(data)))
+		h.Write(size[:])
+		h.Write(data)
+	}
+	return h.Sum(nil), nil
+}
+
+// responseMessage 取出 handler 返回的 protobuf 消息，IDL 模式下包装在 triple Response 中
+func responseMessage(v interface{}) (proto.Message, bool) {
+	if resp, ok := v.(triple_protocol.AnyResponse); ok {
+		v = resp.Any()
+	}
+	m, ok := v.(proto.Message)
+	return m, ok && m != nil
+}
+
+// mustAny 把消息包装为 Any，消息类型名来自生成代码的描述符，不会失败
+func mustAny(m proto.Message) *anypb.Any {
+	a, err := anypb.New(m)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// replayResult 回放保存的响应，与 IDL 模式 handler 的返回值形式一致
+func replayResult(msg proto.Message) result.Result {
+	res := &result.RPCResult{}
+	res.SetResult(triple_protocol.NewResponse(msg))
+	return res
+}
+
+// errorResult 返回带错误的结果
+func errorResult(err error) result.Result {
+	res := &result.RPCResult{}
+	res.SetError(err)
+	return res
+}
+
+// NewKey 生成随机幂等键，供客户端使用
+func NewKey() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
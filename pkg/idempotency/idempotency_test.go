@@ -0,0 +1,260 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"helloworld/greet"
+
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestScopeKeyDoesNotCollide(t *testing.T) {
+	tests := []struct {
+		a, b [3]string // method, caller, key
+	}{
+		{a: [3]string{"Greet", "a:b", "c"}, b: [3]string{"Greet", "a", "b:c"}},
+		{a: [3]string{"Greet:a", "b", "c"}, b: [3]string{"Greet", "a:b", "c"}},
+		{a: [3]string{"Greet", "", "a:c"}, b: [3]string{"Greet", "a", "c"}},
+		{a: [3]string{"Greet", "1:a", "c"}, b: [3]string{"Greet", "1", "a:c"}},
+	}
+	for _, tt := range tests {
+		ka := scopeKey(tt.a[0], tt.a[1], tt.a[2])
+		kb := scopeKey(tt.b[0], tt.b[1], tt.b[2])
+		if ka == kb {
+			t.Errorf("scopeKey(%q) and scopeKey(%q) collide: %s", tt.a, tt.b, ka)
+		}
+	}
+	if scopeKey("Greet", "go-client", "k1") != scopeKey("Greet", "go-client", "k1") {
+		t.Error("scopeKey is not stable")
+	}
+}
+
+// newTestStore 使用进程内 Redis 创建幂等存储
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return New(client, Options{Prefix: "test:idem:"}), mr
+}
+
+// greetHandler 模拟 IDL 模式的 Greet，记录执行次数
+type greetHandler struct {
+	calls  int
+	err    error
+	during func() // 执行期间的回调
+}
+
+func (h *greetHandler) next(req *greet.GreetRequest) func(context.Context) result.Result {
+	return func(context.Context) result.Result {
+		h.calls++
+		if h.during != nil {
+			h.during()
+		}
+		res := &result.RPCResult{}
+		if h.err != nil {
+			res.SetError(h.err)
+			return res
+		}
+		res.SetResult(triple_protocol.NewResponse(&greet.GreetResponse{Greeting: "hello " + req.Name}))
+		return res
+	}
+}
+
+// invokeGreet 以幂等方式执行一次 Greet
+func invokeGreet(s *Store, h *greetHandler, key string, req *greet.GreetRequest) result.Result {
+	return s.invoke(context.Background(), "greet.GreetService.Greet", "go-client", key, []interface{}{req}, h.next(req))
+}
+
+// greeting 取出响应中的 GreetResponse
+func greeting(t *testing.T, res result.Result) string {
+	t.Helper()
+	if res.Error() != nil {
+		t.Fatalf("result error = %v", res.Error())
+	}
+	msg, ok := responseMessage(res.Result())
+	if !ok {
+		t.Fatalf("result = %T, want a protobuf response", res.Result())
+	}
+	resp, ok := msg.(*greet.GreetResponse)
+	if !ok {
+		t.Fatalf("response = %T, want *greet.GreetResponse", msg)
+	}
+	return resp.Greeting
+}
+
+func TestInvokeReplaysResponse(t *testing.T) {
+	s, _ := newTestStore(t)
+	h := &greetHandler{}
+	req := &greet.GreetRequest{Name: "alice"}
+
+	first := greeting(t, invokeGreet(s, h, "k1", req))
+	again := greeting(t, invokeGreet(s, h, "k1", proto.Clone(req).(*greet.GreetRequest)))
+	if h.calls != 1 {
+		t.Errorf("handler calls = %d, want 1", h.calls)
+	}
+	if first != "hello alice" || again != first {
+		t.Errorf("responses = %q, %q, want both hello alice", first, again)
+	}
+
+	// 其他幂等键、其他调用方不受影响
+	invokeGreet(s, h, "k2", req)
+	s.invoke(context.Background(), "greet.GreetService.Greet", "other-app", "k1", []interface{}{req}, h.next(req))
+	if h.calls != 3 {
+		t.Errorf("handler calls = %d, want 3", h.calls)
+	}
+}
+
+func TestInvokeConflict(t *testing.T) {
+	s, _ := newTestStore(t)
+	h := &greetHandler{}
+
+	invokeGreet(s, h, "k1", &greet.GreetRequest{Name: "alice"})
+	res := invokeGreet(s, h, "k1", &greet.GreetRequest{Name: "bob"})
+	if !errors.Is(res.Error(), ErrConflict) {
+		t.Errorf("error = %v, want ErrConflict", res.Error())
+	}
+	if h.calls != 1 {
+		t.Errorf("handler calls = %d, want 1", h.calls)
+	}
+}
+
+func TestInvokeInProgress(t *testing.T) {
+	s, _ := newTestStore(t)
+	h := &greetHandler{}
+	req := &greet.GreetRequest{Name: "alice"}
+
+	// 第一个请求执行期间，相同幂等键的请求被拒绝
+	var inner result.Result
+	h.during = func() {
+		h.during = nil
+		inner = invokeGreet(s, h, "k1", req)
+	}
+	if got := greeting(t, invokeGreet(s, h, "k1", req)); got != "hello alice" {
+		t.Errorf("greeting = %q, want hello alice", got)
+	}
+	if inner == nil || !errors.Is(inner.Error(), ErrInProgress) {
+		t.Errorf("concurrent request result = %v, want ErrInProgress", inner)
+	}
+	if h.calls != 1 {
+		t.Errorf("handler calls = %d, want 1", h.calls)
+	}
+}
+
+func TestInvokeDoesNotSaveFailure(t *testing.T) {
+	s, _ := newTestStore(t)
+	h := &greetHandler{err: triple_protocol.NewError(triple_protocol.CodeInternal, errors.New("db down"))}
+	req := &greet.GreetRequest{Name: "alice"}
+
+	if res := invokeGreet(s, h, "k1", req); res.Error() == nil {
+		t.Fatal("error was not returned")
+	}
+	// 失败后用相同幂等键重试会再次执行
+	h.err = nil
+	if got := greeting(t, invokeGreet(s, h, "k1", req)); got != "hello alice" {
+		t.Errorf("greeting = %q, want hello alice", got)
+	}
+	if h.calls != 2 {
+		t.Errorf("handler calls = %d, want 2", h.calls)
+	}
+}
+
+func TestInvokeDoesNotSaveWhenLockLost(t *testing.T) {
+	s, mr := newTestStore(t)
+	h := &greetHandler{}
+	req := &greet.GreetRequest{Name: "alice"}
+	scope := scopeKey("greet.GreetService.Greet", "go-client", "k1")
+
+	// 执行期间锁过期并被其他请求取得
+	h.during = func() {
+		if err := mr.Set("test:idem:lock:{"+scope+"}", "someone-else"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	greeting(t, invokeGreet(s, h, "k1", req))
+	if mr.Exists("test:idem:resp:{" + scope + "}") {
+		t.Error("response saved after the lock was lost")
+	}
+	if got, _ := mr.Get("test:idem:lock:{" + scope + "}"); got != "someone-else" {
+		t.Errorf("lock value = %q, want the new holder's", got)
+	}
+}
+
+func TestInvokeSavesWithTTL(t *testing.T) {
+	s, mr := newTestStore(t)
+	h := &greetHandler{}
+	req := &greet.GreetRequest{Name: "alice"}
+	scope := scopeKey("greet.GreetService.Greet", "go-client", "k1")
+
+	invokeGreet(s, h, "k1", req)
+	if ttl := mr.TTL("test:idem:resp:{" + scope + "}"); ttl != 24*time.Hour {
+		t.Errorf("response ttl = %v, want 24h", ttl)
+	}
+	if mr.Exists("test:idem:lock:{" + scope + "}") {
+		t.Error("lock was not released")
+	}
+
+	// 过期后重新执行
+	mr.FastForward(25 * time.Hour)
+	invokeGreet(s, h, "k1", req)
+	if h.calls != 2 {
+		t.Errorf("handler calls = %d, want 2", h.calls)
+	}
+}
+
+func TestInvokeWithoutRedis(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	s := New(client, Options{Prefix: "test:idem:"})
+	h := &greetHandler{}
+	req := &greet.GreetRequest{Name: "alice"}
+
+	// Redis 不可用时直接执行
+	for i := 0; i < 2; i++ {
+		if got := greeting(t, invokeGreet(s, h, "k1", req)); got != "hello alice" {
+			t.Errorf("greeting = %q, want hello alice", got)
+		}
+	}
+	if h.calls != 2 {
+		t.Errorf("handler calls = %d, want 2", h.calls)
+	}
+}
+
+func TestInvokeRejectsLongKey(t *testing.T) {
+	s, _ := newTestStore(t)
+	h := &greetHandler{}
+	key := string(make([]byte, maxKeyLength+1))
+
+	res := invokeGreet(s, h, key, &greet.GreetRequest{Name: "alice"})
+	var terr *triple_protocol.Error
+	if !errors.As(res.Error(), &terr) || terr.Code() != triple_protocol.CodeInvalidArgument {
+		t.Errorf("error = %v, want invalid argument", res.Error())
+	}
+	if h.calls != 0 {
+		t.Errorf("handler calls = %d, want 0", h.calls)
+	}
+}
+
+func TestInvokeNonProtoRequest(t *testing.T) {
+	s, mr := newTestStore(t)
+	h := &greetHandler{}
+	req := &greet.GreetRequest{Name: "alice"}
+
+	// 非 IDL 模式的参数无法计算指纹，不做幂等处理
+	for i := 0; i < 2; i++ {
+		s.invoke(context.Background(), "Greet", "go-client", "k1", []interface{}{"alice"}, h.next(req))
+	}
+	if h.calls != 2 {
+		t.Errorf("handler calls = %d, want 2", h.calls)
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Errorf("redis keys = %v, want none", keys)
+	}
+}
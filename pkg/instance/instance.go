@@ -2,34 +2,49 @@ package instance
 
 import (
 	"helloworld/pkg/config"
+	"helloworld/pkg/log"
 
 	"dubbo.apache.org/dubbo-go/v3"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/registry"
-
-	"github.com/dubbogo/gost/log/logger"
 )
 
+// logger instance 包日志
+var logger = log.Named("helloworld/pkg/instance")
+
 func InitInstance(cfg *config.Config) (*dubbo.Instance, error) {
-	ins, err := dubbo.NewInstance(
+	registryOpts := []registry.Option{
+		registry.WithNacos(),
+		registry.WithAddress(cfg.Nacos.Address),
+	}
+	// 服务注册到与配置相同的命名空间，不同环境的实例互不可见；public 命名空间在 Nacos 中的 ID 为空
+	if cfg.Nacos.Namespace != "" && cfg.Nacos.Namespace != "public" {
+		registryOpts = append(registryOpts, registry.WithNamespace(cfg.Nacos.Namespace))
+	}
+
+	opts := []dubbo.InstanceOption{
 		dubbo.WithName(cfg.AppName),
-		dubbo.WithConfigCenter(
-			config_center.WithNacos(),
-			config_center.WithDataID(cfg.AppName),
-			config_center.WithAddress(cfg.Nacos.Address),
-			config_center.WithNamespace(cfg.Nacos.Namespace),
-			config_center.WithGroup(cfg.Nacos.Group),
-		),
-		dubbo.WithRegistry(
-			registry.WithNacos(),
-			registry.WithAddress(cfg.Nacos.Address),
-		),
+		dubbo.WithRegistry(registryOpts...),
 		dubbo.WithProtocol(
 			protocol.WithTriple(),
 			protocol.WithPort(cfg.AppPort),
 		),
-	)
+	}
+	// 应用配置来自本地文件时不连接 Nacos 配置中心
+	if cfg.ConfigFile == "" {
+		opts = append(opts, dubbo.WithConfigCenter(
+			config_center.WithNacos(),
+			config_center.WithDataID(cfg.Nacos.DataID),
+			config_center.WithAddress(cfg.Nacos.Address),
+			config_center.WithNamespace(cfg.Nacos.Namespace),
+			config_center.WithGroup(cfg.Nacos.Group),
+		))
+	} else {
+		logger.Infof("App config from local file %s, Nacos config center disabled", cfg.ConfigFile)
+	}
+
+	ins, err := dubbo.NewInstance(opts...)
 	if err != nil {
 		logger.Errorf("new dubbo instance failed: %v", err)
 		panic(err)
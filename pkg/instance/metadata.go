@@ -0,0 +1,246 @@
+package instance
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"helloworld/pkg/buildinfo"
+	"helloworld/pkg/config"
+
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	dubbonacos "dubbo.apache.org/dubbo-go/v3/remoting/nacos"
+	nacosClient "github.com/dubbogo/gost/database/kv/nacos"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// 注册到 Nacos 的实例元数据，用于排查各实例运行的版本和配置
+const (
+	MetaVersion    = "app.version"
+	MetaCommit     = "app.commit"
+	MetaBuildTime  = "app.build-time"
+	MetaConfigHash = "app.config-hash"
+)
+
+// metadataPriority 在 dubbo 内置的 customizer 之后执行
+const metadataPriority = 100
+
+// metaInstanceID dubbo 把实例 ID 放在 Nacos 元数据的 id 中
+const metaInstanceID = "id"
+
+// registeredInstance 已注册的实例和注册时元数据的副本
+// 刷新时只修改副本，dubbo 和 Nacos 客户端持有的元数据 map 不做改动
+type registeredInstance struct {
+	instance registry.ServiceInstance
+	metadata map[string]string
+}
+
+var (
+	publishOnce sync.Once
+	refreshCh   = make(chan struct{}, 1) // 待刷新的信号，多次配置变化合并为一次
+
+	metaMu        sync.Mutex
+	registered    []registeredInstance                          // 已注册的实例，按地址去重
+	publishedHash string                                        // Nacos 中实例元数据当前的配置 hash
+	namingClients = map[string]*nacosClient.NacosNamingClient{} // 按注册中心缓存，与 dubbo 共用同一个连接
+)
+
+// PublishMetadata 把构建信息和当前配置 hash 写入注册中心的实例元数据，配置更新后重新注册以刷新 hash
+// 需要在 server.Serve 之前调用
+func PublishMetadata() {
+	publishOnce.Do(func() {
+		extension.AddCustomizers(&metadataCustomizer{})
+		go refreshLoop()
+		// 监听者在配置生效的路径上同步执行（持有 applyMu），这里只发信号，不访问 Nacos，
+		// 避免 Nacos 缓慢或不可达时阻塞配置推送、回滚和固定版本
+		config.RegisterChangeListener(func(map[string]interface{}) {
+			select {
+			case refreshCh <- struct{}{}:
+			default:
+				// 已有待处理的刷新，执行时读取最新的 hash
+			}
+		})
+	})
+}
+
+// refreshLoop 后台刷新实例元数据，每次使用执行时最新的配置 hash
+func refreshLoop() {
+	for range refreshCh {
+		refreshMetadata()
+	}
+}
+
+// metadataCustomizer 服务实例注册前写入元数据
+type metadataCustomizer struct{}
+
+// GetPriority 执行顺序，越小越先执行
+func (c *metadataCustomizer) GetPriority() int {
+	return metadataPriority
+}
+
+// Customize 写入构建信息和配置 hash，并记录实例用于之后刷新；此时实例尚未注册，可以直接修改元数据
+func (c *metadataCustomizer) Customize(instance registry.ServiceInstance) {
+	md := instance.GetMetadata()
+	if md == nil {
+		return
+	}
+	info := buildinfo.Get()
+	hash := config.Snapshot().Hash()
+	md[MetaVersion] = info.Version
+	md[MetaCommit] = info.Commit
+	md[MetaBuildTime] = info.BuildTime
+	md[MetaConfigHash] = hash
+
+	entry := registeredInstance{instance: instance, metadata: copyMetadata(md)}
+	metaMu.Lock()
+	defer metaMu.Unlock()
+	publishedHash = hash
+	for i, r := range registered {
+		if r.instance.GetAddress() == instance.GetAddress() {
+			registered[i] = entry
+			return
+		}
+	}
+	registered = append(registered, entry)
+}
+
+// refreshMetadata 配置 hash 变化时更新实例元数据，只在 refreshLoop 中执行
+// 通过 dubbo 共用的 Nacos 客户端按服务批量注册，Nacos 用这一批实例替换该连接之前注册的实例，不会先注销；
+// 不经过 ServiceDiscovery.Register，它每次调用都会追加实例，重复注册会越积越多
+func refreshMetadata() {
+	hash := config.Snapshot().Hash()
+
+	metaMu.Lock()
+	defer metaMu.Unlock()
+	if len(registered) == 0 || hash == publishedHash {
+		return
+	}
+	for _, r := range registered {
+		r.metadata[MetaConfigHash] = hash
+	}
+
+	ok := true
+	for _, url := range nacosRegistryURLs() {
+		if err := republish(url); err != nil {
+			logger.Errorf("Failed to update instance metadata in %s: %v", url.Location, err)
+			ok = false
+		}
+	}
+	// 失败时不更新 publishedHash，下一次配置变化时重试
+	if ok {
+		publishedHash = hash
+		logger.Infof("Instance metadata updated: %s=%s", MetaConfigHash, hash)
+	}
+}
+
+// republish 向一个 Nacos 注册中心重新批量注册全部实例，调用方持有 metaMu
+func republish(url *common.URL) error {
+	client, err := namingClient(url)
+	if err != nil {
+		return err
+	}
+	group := url.GetParam(constant.RegistryGroupKey, constant.ServiceDiscoveryDefaultGroup)
+
+	byService := make(map[string][]vo.RegisterInstanceParam)
+	for _, r := range registered {
+		ins := r.instance
+		md := copyMetadata(r.metadata)
+		md[metaInstanceID] = ins.GetID()
+		byService[ins.GetServiceName()] = append(byService[ins.GetServiceName()], vo.RegisterInstanceParam{
+			ServiceName: ins.GetServiceName(),
+			Ip:          ins.GetHost(),
+			Port:        uint64(ins.GetPort()),
+			Metadata:    md,
+			Weight:      instanceWeight(ins, url),
+			Enable:      ins.IsEnable(),
+			Healthy:     ins.IsHealthy(),
+			GroupName:   group,
+			Ephemeral:   true,
+		})
+	}
+	for service, instances := range byService {
+		ok, err := client.Client().BatchRegisterInstance(vo.BatchRegisterInstanceParam{
+			ServiceName: service,
+			GroupName:   group,
+			Instances:   instances,
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("batch register %s rejected", service)
+		}
+	}
+	return nil
+}
+
+// namingClient 获取 dubbo 服务发现使用的 Nacos 客户端；dubbo 按客户端名称共享连接，参数与 dubbo 创建服务发现时一致
+func namingClient(url *common.URL) (*nacosClient.NacosNamingClient, error) {
+	key := url.Location + "?" + url.GetParam(constant.RegistryNamespaceKey, "")
+	if c, ok := namingClients[key]; ok {
+		return c, nil
+	}
+	discoveryURL := common.NewURLWithOptions(
+		common.WithParams(url.GetParams()),
+		common.WithParamsValue(constant.TimeoutKey, url.GetParam(constant.RegistryTimeoutKey, constant.DefaultRegTimeout)),
+		common.WithParamsValue(constant.NacosGroupKey, url.GetParam(constant.RegistryGroupKey, constant.ServiceDiscoveryDefaultGroup)),
+		common.WithParamsValue(constant.NacosUsername, url.Username),
+		common.WithParamsValue(constant.NacosPassword, url.Password),
+		common.WithParamsValue(constant.NacosNamespaceID, url.GetParam(constant.RegistryNamespaceKey, "")))
+	discoveryURL.Location = url.Location
+	discoveryURL.Username = url.Username
+	discoveryURL.Password = url.Password
+	c, err := dubbonacos.NewNacosClientByURL(discoveryURL)
+	if err != nil {
+		return nil, err
+	}
+	namingClients[key] = c
+	return c, nil
+}
+
+// instanceWeight 与 dubbo 注册时的权重一致：registry.weight 优先，限制在 Nacos 允许的范围内
+func instanceWeight(ins registry.ServiceInstance, url *common.URL) float64 {
+	w := ins.GetWeight()
+	if v := url.GetParam(constant.RegistryKey+"."+constant.WeightKey, ""); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			w = int64(f)
+		}
+	}
+	switch {
+	case w <= 0:
+		w = int64(constant.DefaultNacosWeight)
+	case w > constant.MaxNacosWeight:
+		w = constant.MaxNacosWeight
+	}
+	return float64(w)
+}
+
+// nacosRegistryURLs 应用级服务发现使用的 Nacos 注册中心，实例注册在这里
+func nacosRegistryURLs() []*common.URL {
+	rf, ok := extension.GetProtocol(constant.RegistryKey).(registry.RegistryFactory)
+	if !ok {
+		return nil
+	}
+	var urls []*common.URL
+	for _, r := range rf.GetRegistries() {
+		if _, ok := r.(registry.ServiceDiscoveryRegistry); !ok {
+			continue
+		}
+		if url := r.GetURL(); url.GetParam(constant.RegistryKey, "") == constant.NacosKey {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// copyMetadata 复制元数据
+func copyMetadata(md map[string]string) map[string]string {
+	cp := make(map[string]string, len(md)+1)
+	for k, v := range md {
+		cp[k] = v
+	}
+	return cp
+}
@@ -0,0 +1,248 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"helloworld/pkg/log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// logger 分布式锁日志，看门狗续期失败时输出
+var logger = log.Named("helloworld/pkg/lock")
+
+var (
+	// ErrNotObtained 在等待时间内没有拿到锁
+	ErrNotObtained = errors.New("lock: not obtained")
+	// ErrLockNotHeld 锁已过期或被其他持有者占用
+	ErrLockNotHeld = errors.New("lock: not held")
+)
+
+// obtainScript 加锁成功时递增并返回 fencing token，失败返回 0
+// KEYS[1] 锁 key，KEYS[2] fencing 计数器 key，ARGV[1] 持有者标识，ARGV[2] 过期毫秒数
+var obtainScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+// releaseScript 只有持有者本人才能释放锁
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// refreshScript 只有持有者本人才能续期
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// setIfHeldScript 仍持有锁时写入 key，检查和写入在 Redis 中原子执行
+// KEYS[1] 锁 key，KEYS[2] 写入的 key，ARGV[1] 持有者标识，ARGV[2] 写入的值，ARGV[3] 过期毫秒数
+var setIfHeldScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[2], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// Options 加锁选项
+type Options struct {
+	TTL           time.Duration // 锁过期时间，默认 10s
+	WaitTimeout   time.Duration // 等待锁的最长时间，0 表示只尝试一次
+	RetryInterval time.Duration // 重试间隔，默认 50ms
+	AutoRenew     bool          // 是否启动看门狗自动续期
+}
+
+// Locker 基于 Redis 的分布式锁
+type Locker struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewLocker 创建分布式锁，prefix 为锁 key 前缀
+func NewLocker(client *redis.Client, prefix string) *Locker {
+	return &Locker{client: client, prefix: prefix}
+}
+
+// Obtain 获取锁，拿不到时返回 ErrNotObtained
+func (l *Locker) Obtain(ctx context.Context, key string, opts Options) (*Lock, error) {
+	if l == nil || l.client == nil {
+		return nil, fmt.Errorf("lock: redis client is nil")
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 10 * time.Second
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = 50 * time.Millisecond
+	}
+
+	value, err := randomValue()
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用 hash tag 保证锁和 fencing 计数器在 Redis Cluster 的同一个 slot
+	lockKey := fmt.Sprintf("%s{%s}", l.prefix, key)
+	fenceKey := lockKey + ":fence"
+
+	var deadline time.Time
+	if opts.WaitTimeout > 0 {
+		deadline = time.Now().Add(opts.WaitTimeout)
+	}
+
+	for {
+		token, err := obtainScript.Run(ctx, l.client, []string{lockKey, fenceKey},
+			value, opts.TTL.Milliseconds()).Int64()
+		if err != nil {
+			return nil, fmt.Errorf("lock: obtain %s failed: %w", key, err)
+		}
+		if token > 0 {
+			lk := &Lock{
+				client: l.client,
+				key:    lockKey,
+				value:  value,
+				token:  token,
+				ttl:    opts.TTL,
+				lost:   make(chan struct{}),
+				stop:   make(chan struct{}),
+			}
+			if opts.AutoRenew {
+				lk.wg.Add(1)
+				go lk.watchdog()
+			}
+			return lk, nil
+		}
+
+		if deadline.IsZero() || time.Now().Add(opts.RetryInterval).After(deadline) {
+			return nil, ErrNotObtained
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(opts.RetryInterval):
+		}
+	}
+}
+
+// Lock 已获取的锁
+type Lock struct {
+	client *redis.Client
+	key    string
+	value  string
+	token  int64
+	ttl    time.Duration
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// Token 返回 fencing token，单调递增
+// 写存储时带上 token 并拒绝比已见过的更小的 token，可以防止锁过期后旧持有者的写入
+func (lk *Lock) Token() int64 {
+	return lk.token
+}
+
+// Lost 看门狗续期失败（锁已丢失）时关闭
+func (lk *Lock) Lost() <-chan struct{} {
+	return lk.lost
+}
+
+// Refresh 续期锁
+func (lk *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	n, err := refreshScript.Run(ctx, lk.client, []string{lk.key}, lk.value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("lock: refresh failed: %w", err)
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// SetIfHeld 仍持有锁时写入 key，锁已过期或被他人占用时返回 ErrLockNotHeld
+// 检查锁和写入原子执行，避免锁在检查之后过期、覆盖新持有者的写入；
+// Redis Cluster 下 key 需要包含与锁相同的 hash tag，即 "{" + Obtain 的 key + "}"
+func (lk *Lock) SetIfHeld(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	n, err := setIfHeldScript.Run(ctx, lk.client, []string{lk.key, key}, lk.value, value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("lock: set %s failed: %w", key, err)
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Release 释放锁，同时停止看门狗
+func (lk *Lock) Release(ctx context.Context) error {
+	lk.stopOnce.Do(func() { close(lk.stop) })
+	lk.wg.Wait()
+
+	n, err := releaseScript.Run(ctx, lk.client, []string{lk.key}, lk.value).Int64()
+	if err != nil {
+		return fmt.Errorf("lock: release failed: %w", err)
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// watchdog 每 TTL/3 续期一次，锁被他人占用或一直续期失败到过期时标记为丢失
+func (lk *Lock) watchdog() {
+	defer lk.wg.Done()
+
+	interval := lk.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	expireAt := time.Now().Add(lk.ttl)
+	for {
+		select {
+		case <-lk.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := lk.Refresh(ctx, lk.ttl)
+			cancel()
+
+			switch {
+			case err == nil:
+				expireAt = time.Now().Add(lk.ttl)
+			case errors.Is(err, ErrLockNotHeld) || time.Now().After(expireAt):
+				logger.Errorf("lock: %s lost: %v", lk.key, err)
+				lk.lostOnce.Do(func() { close(lk.lost) })
+				return
+			default:
+				logger.Warnf("lock: renew %s failed, will retry: %v", lk.key, err)
+			}
+		}
+	}
+}
+
+// randomValue 生成持有者标识
+func randomValue() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("lock: failed to generate value: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
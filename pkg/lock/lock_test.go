@@ -0,0 +1,165 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestLocker 使用进程内 Redis 创建分布式锁
+func newTestLocker(t *testing.T) (*Locker, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewLocker(client, "test:lock:"), mr
+}
+
+func TestObtainAndRelease(t *testing.T) {
+	l, mr := newTestLocker(t)
+	ctx := context.Background()
+
+	lk, err := l.Obtain(ctx, "k", Options{TTL: time.Second})
+	if err != nil {
+		t.Fatalf("Obtain() error = %v", err)
+	}
+	if _, err := l.Obtain(ctx, "k", Options{TTL: time.Second}); !errors.Is(err, ErrNotObtained) {
+		t.Errorf("second Obtain() error = %v, want ErrNotObtained", err)
+	}
+	if ttl := mr.TTL("test:lock:{k}"); ttl != time.Second {
+		t.Errorf("lock ttl = %v, want 1s", ttl)
+	}
+
+	if err := lk.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if mr.Exists("test:lock:{k}") {
+		t.Error("lock key still exists after Release()")
+	}
+	if err := lk.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("second Release() error = %v, want ErrLockNotHeld", err)
+	}
+
+	// fencing token 单调递增
+	next, err := l.Obtain(ctx, "k", Options{TTL: time.Second})
+	if err != nil {
+		t.Fatalf("Obtain() after release error = %v", err)
+	}
+	if next.Token() <= lk.Token() {
+		t.Errorf("token = %d, want greater than %d", next.Token(), lk.Token())
+	}
+}
+
+func TestReleaseDoesNotDeleteOthersLock(t *testing.T) {
+	l, mr := newTestLocker(t)
+	ctx := context.Background()
+
+	lk, err := l.Obtain(ctx, "k", Options{TTL: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 锁过期后被其他持有者取得
+	mr.FastForward(2 * time.Second)
+	other, err := l.Obtain(ctx, "k", Options{TTL: time.Second})
+	if err != nil {
+		t.Fatalf("Obtain() after expiry error = %v", err)
+	}
+
+	if err := lk.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("stale Release() error = %v, want ErrLockNotHeld", err)
+	}
+	if err := lk.SetIfHeld(ctx, "test:resp:{k}", "stale", time.Minute); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("stale SetIfHeld() error = %v, want ErrLockNotHeld", err)
+	}
+	if mr.Exists("test:resp:{k}") {
+		t.Error("stale holder wrote the key")
+	}
+
+	if err := other.SetIfHeld(ctx, "test:resp:{k}", "fresh", time.Minute); err != nil {
+		t.Fatalf("SetIfHeld() error = %v", err)
+	}
+	if got, _ := mr.Get("test:resp:{k}"); got != "fresh" {
+		t.Errorf("value = %q, want fresh", got)
+	}
+	if ttl := mr.TTL("test:resp:{k}"); ttl != time.Minute {
+		t.Errorf("value ttl = %v, want 1m", ttl)
+	}
+	if err := other.Release(ctx); err != nil {
+		t.Errorf("Release() error = %v", err)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	l, _ := newTestLocker(t)
+	ctx := context.Background()
+
+	lk, err := l.Obtain(ctx, "k", Options{TTL: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = lk.Release(ctx)
+	}()
+	waited, err := l.Obtain(ctx, "k", Options{TTL: time.Second, WaitTimeout: time.Second, RetryInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Obtain() with wait error = %v", err)
+	}
+	_ = waited.Release(ctx)
+}
+
+func TestWatchdogRenews(t *testing.T) {
+	l, mr := newTestLocker(t)
+	ctx := context.Background()
+
+	lk, err := l.Obtain(ctx, "k", Options{TTL: 300 * time.Millisecond, AutoRenew: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lk.Release(ctx)
+
+	// miniredis 不会自动流逝时间：先消耗大部分 TTL，再等看门狗续期
+	mr.FastForward(250 * time.Millisecond)
+	time.Sleep(250 * time.Millisecond)
+	if ttl := mr.TTL("test:lock:{k}"); ttl <= 100*time.Millisecond {
+		t.Errorf("lock ttl = %v, want renewed to about 300ms", ttl)
+	}
+	select {
+	case <-lk.Lost():
+		t.Error("lock reported lost while renewing")
+	default:
+	}
+}
+
+func TestWatchdogReportsLost(t *testing.T) {
+	l, mr := newTestLocker(t)
+	ctx := context.Background()
+
+	lk, err := l.Obtain(ctx, "k", Options{TTL: 150 * time.Millisecond, AutoRenew: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 其他持有者占用了锁
+	if err := mr.Set("test:lock:{k}", "someone-else"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-lk.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() was not closed")
+	}
+	if err := lk.SetIfHeld(ctx, "test:resp:{k}", "v", time.Minute); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("SetIfHeld() after lost error = %v, want ErrLockNotHeld", err)
+	}
+	if err := lk.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("Release() after lost error = %v, want ErrLockNotHeld", err)
+	}
+	if got, _ := mr.Get("test:lock:{k}"); got != "someone-else" {
+		t.Errorf("lock value = %q, want the other holder's", got)
+	}
+}
@@ -0,0 +1,299 @@
+package log
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// OffLevel 关闭日志，用于 Policy.Levels 中屏蔽某个 logger
+const OffLevel = zapcore.FatalLevel + 1
+
+// Policy 可热更新的日志策略，输出目标不变，只替换过滤层
+type Policy struct {
+	Level          zapcore.Level            // 全局级别
+	Levels         map[string]zapcore.Level // 按 logger 名称（包路径）设置级别，最长前缀匹配
+	Sampling       *SamplingPolicy          // 采样，nil 表示不采样
+	ErrorRateLimit *RateLimitPolicy         // 相同错误限频，nil 表示不限频
+}
+
+// SamplingPolicy 采样策略：每个 Tick 内相同级别、相同消息的日志先输出 Initial 条，之后每 Thereafter 条输出 1 条
+type SamplingPolicy struct {
+	Tick       time.Duration
+	Initial    int
+	Thereafter int
+	Levels     map[zapcore.Level]SamplingRule // 按级别覆盖 Initial/Thereafter
+}
+
+// SamplingRule 单个级别的采样规则，Initial 为 0 时该级别不采样
+type SamplingRule struct {
+	Initial    int
+	Thereafter int
+}
+
+// RateLimitPolicy 相同错误限频：同一 logger、同一消息的 error 及以上日志每个 Interval 最多输出 Burst 条
+// 被丢弃的条数在下一个窗口的第一条日志中以 suppressed 字段输出
+type RateLimitPolicy struct {
+	Interval time.Duration
+	Burst    int
+}
+
+// state 当前生效的输出和过滤层
+type state struct {
+	sink    zapcore.Core // 输出目标（不含过滤），由 SetOutput 设置
+	policy  Policy
+	limiter *errorLimiter // 相同错误限频计数，限频参数不变时跨 Configure 保留
+	core    zapcore.Core  // sink 叠加 policy 后的 core
+}
+
+var current atomic.Pointer[state]
+
+func init() {
+	sink := zapcore.NewCore(
+		zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()),
+		zapcore.Lock(os.Stderr),
+		zapcore.DebugLevel,
+	)
+	swap(sink, Policy{Level: zapcore.DebugLevel})
+}
+
+// SetOutput 替换输出目标，sink 应以最低级别（Debug）创建，级别过滤由 Policy 负责
+func SetOutput(sink zapcore.Core) {
+	if sink == nil {
+		return
+	}
+	swap(sink, current.Load().policy)
+}
+
+// Configure 替换日志策略（级别、采样、限频），已创建的 logger 立即生效
+func Configure(p Policy) {
+	swap(current.Load().sink, p)
+}
+
+// swap 按 sink 和 policy 构建新的 core 并原子替换
+func swap(sink zapcore.Core, p Policy) {
+	core := sink
+	if p.Sampling != nil {
+		core = newSamplingCore(core, p.Sampling)
+	}
+	var limiter *errorLimiter
+	if p.ErrorRateLimit != nil && p.ErrorRateLimit.Interval > 0 && p.ErrorRateLimit.Burst > 0 {
+		// 配置推送通常只改级别，限频参数不变时沿用原来的计数，否则推送后被限频的错误会立即重新输出
+		if old := current.Load(); old != nil && old.limiter != nil &&
+			old.limiter.interval == p.ErrorRateLimit.Interval && old.limiter.burst == p.ErrorRateLimit.Burst {
+			limiter = old.limiter
+		} else {
+			limiter = newErrorLimiter(p.ErrorRateLimit)
+		}
+		core = &rateLimitCore{Core: core, limiter: limiter}
+	}
+	core = newLevelCore(core, p.Level, p.Levels)
+	current.Store(&state{sink: sink, policy: p, limiter: limiter, core: core})
+}
+
+// dynamicCore 转发到当前生效的 core，Configure/SetOutput 后已创建的 logger 无需重建
+type dynamicCore struct {
+	fields []zapcore.Field
+	// ctxFields 上下文字段（FromContext），写入时追加：每个请求的字段不同，派生的 core 无法复用
+	ctxFields []zapcore.Field
+	// derived 带 fields 的 core，按 state 缓存：With 会重建过滤层并复制 encoder，不能每条日志都调用
+	// withContext 得到的 core 与原 core 共享缓存
+	derived *atomic.Pointer[derivedCore]
+}
+
+// derivedCore 由某一版 state 派生的 core，state 替换后重新派生
+type derivedCore struct {
+	state *state
+	core  zapcore.Core
+}
+
+// load 返回当前 state 对应的 core，带 fields 时使用缓存的派生 core
+func (c *dynamicCore) load() zapcore.Core {
+	st := current.Load()
+	if len(c.fields) == 0 {
+		return st.core
+	}
+	if d := c.derived.Load(); d != nil && d.state == st {
+		return d.core
+	}
+	core := st.core.With(c.fields)
+	c.derived.Store(&derivedCore{state: st, core: core})
+	return core
+}
+
+// withContext 返回写入时追加 fields 的 core
+func (c *dynamicCore) withContext(fields []zapcore.Field) *dynamicCore {
+	merged := make([]zapcore.Field, 0, len(c.ctxFields)+len(fields))
+	merged = append(merged, c.ctxFields...)
+	merged = append(merged, fields...)
+	return &dynamicCore{fields: c.fields, ctxFields: merged, derived: c.derived}
+}
+
+func (c *dynamicCore) Enabled(lvl zapcore.Level) bool {
+	return c.load().Enabled(lvl)
+}
+
+func (c *dynamicCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &dynamicCore{fields: merged, ctxFields: c.ctxFields, derived: new(atomic.Pointer[derivedCore])}
+}
+
+func (c *dynamicCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// 先用未派生的 core 判断级别，被过滤的日志不触发派生
+	if !current.Load().core.Enabled(ent.Level) {
+		return ce
+	}
+	if len(c.ctxFields) == 0 {
+		return c.load().Check(ent, ce)
+	}
+	// 由内层 core 完成级别、采样、限频判断，写入时再追加上下文字段
+	checked := c.load().Check(ent, nil)
+	if checked == nil {
+		return ce
+	}
+	return ce.AddCore(ent, &contextWriter{checked: checked, fields: c.ctxFields})
+}
+
+func (c *dynamicCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.load().Write(ent, appendFields(c.ctxFields, fields))
+}
+
+func (c *dynamicCore) Sync() error {
+	return c.load().Sync()
+}
+
+// contextWriter 写入时在字段前追加上下文字段，再交给内层 core 已通过检查的 CheckedEntry
+type contextWriter struct {
+	checked *zapcore.CheckedEntry
+	fields  []zapcore.Field
+}
+
+func (w *contextWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *contextWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *contextWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+// Write 内层 CheckedEntry 在 logger 填充调用位置之前创建，写入前使用最终的 Entry
+// 内层 CheckedEntry 没有设置 ErrorOutput，写入错误被忽略
+func (w *contextWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	w.checked.Entry = ent
+	w.checked.Write(appendFields(w.fields, fields)...)
+	return nil
+}
+
+func (w *contextWriter) Sync() error { return nil }
+
+// appendFields 合并上下文字段和本条日志的字段
+func appendFields(ctxFields, fields []zapcore.Field) []zapcore.Field {
+	if len(ctxFields) == 0 {
+		return fields
+	}
+	merged := make([]zapcore.Field, 0, len(ctxFields)+len(fields))
+	merged = append(merged, ctxFields...)
+	return append(merged, fields...)
+}
+
+// levelCore 按 logger 名称过滤级别
+type levelCore struct {
+	zapcore.Core
+	level  zapcore.Level
+	levels map[string]zapcore.Level
+	min    zapcore.Level // 所有级别中的最低级别，用于快速判断
+}
+
+func newLevelCore(core zapcore.Core, level zapcore.Level, levels map[string]zapcore.Level) *levelCore {
+	min := level
+	for _, l := range levels {
+		if l < min {
+			min = l
+		}
+	}
+	return &levelCore{Core: core, level: level, levels: levels, min: min}
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= c.min
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level, levels: c.levels, min: c.min}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.levelFor(ent.LoggerName) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// levelFor 查找 logger 名称对应的级别，"helloworld/pkg/config" 同时匹配 "helloworld/pkg/config.xxx" 和子包
+func (c *levelCore) levelFor(name string) zapcore.Level {
+	level, matched := c.level, -1
+	if name == "" {
+		return level
+	}
+	for prefix, l := range c.levels {
+		if len(prefix) <= matched {
+			continue
+		}
+		if name == prefix || strings.HasPrefix(name, prefix+".") || strings.HasPrefix(name, prefix+"/") {
+			level, matched = l, len(prefix)
+		}
+	}
+	return level
+}
+
+// samplingCore 按级别使用不同的采样参数
+type samplingCore struct {
+	zapcore.Core
+	byLevel map[zapcore.Level]zapcore.Core
+}
+
+func newSamplingCore(core zapcore.Core, p *SamplingPolicy) zapcore.Core {
+	tick := p.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	byLevel := make(map[zapcore.Level]zapcore.Core)
+	for lvl := zapcore.DebugLevel; lvl <= zapcore.FatalLevel; lvl++ {
+		rule := SamplingRule{Initial: p.Initial, Thereafter: p.Thereafter}
+		if r, ok := p.Levels[lvl]; ok {
+			rule = r
+		}
+		if rule.Initial <= 0 {
+			continue
+		}
+		byLevel[lvl] = zapcore.NewSamplerWithOptions(core, tick, rule.Initial, rule.Thereafter)
+	}
+	return &samplingCore{Core: core, byLevel: byLevel}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	byLevel := make(map[zapcore.Level]zapcore.Core, len(c.byLevel))
+	for lvl, core := range c.byLevel {
+		byLevel[lvl] = core.With(fields)
+	}
+	return &samplingCore{Core: c.Core.With(fields), byLevel: byLevel}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if core, ok := c.byLevel[ent.Level]; ok {
+		return core.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// Named 返回指定名称的子 logger，名称一般使用包路径，例如 "helloworld/pkg/config"
+// 子 logger 的级别由 Policy.Levels 控制，热更新后立即生效
+func Named(name string) *zap.SugaredLogger {
+	return root.Named(name).Sugar()
+}
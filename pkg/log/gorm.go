@@ -0,0 +1,126 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// GormLoggerName GORM 日志使用的 logger 名称，可通过 log.levels.gorm 或 mysql.log_level 调整级别
+const GormLoggerName = "gorm"
+
+// GormOptions GORM 日志选项
+type GormOptions struct {
+	SlowThreshold             time.Duration // 慢查询阈值，0 表示不记录慢查询
+	IgnoreRecordNotFoundError bool          // 不把 ErrRecordNotFound 当作错误记录
+	RedactParams              bool          // SQL 中的参数替换为 ?，避免输出密码、手机号等敏感数据
+}
+
+// GormLogger 将 GORM 日志写入 zap：SQL 错误为 error，慢查询为 warn，其余 SQL 为 debug
+// 实际输出哪些由 "gorm" logger 的级别决定，修改 log.levels 后热更新
+type GormLogger struct {
+	opts  GormOptions
+	level gormlogger.LogLevel
+	zl    *zap.Logger
+}
+
+// NewGormLogger 创建 GORM 日志适配器
+func NewGormLogger(opts GormOptions) *GormLogger {
+	return &GormLogger{
+		opts:  opts,
+		level: gormlogger.Info,
+		// 调用位置由 GORM 的 utils.FileWithLineNum 提供
+		zl: root.Named(GormLoggerName).WithOptions(zap.WithCaller(false)),
+	}
+}
+
+// LogMode 实现 gormlogger.Interface，db.Debug() 等会调用
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info 实现 gormlogger.Interface
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	l.log(ctx, gormlogger.Info, zapcore.InfoLevel, fmt.Sprintf(msg, args...))
+}
+
+// Warn 实现 gormlogger.Interface
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	l.log(ctx, gormlogger.Warn, zapcore.WarnLevel, fmt.Sprintf(msg, args...))
+}
+
+// Error 实现 gormlogger.Interface
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	l.log(ctx, gormlogger.Error, zapcore.ErrorLevel, fmt.Sprintf(msg, args...))
+}
+
+// Trace 实现 gormlogger.Interface，记录 SQL、影响行数和耗时
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+
+	var (
+		zapLevel zapcore.Level
+		msg      string
+	)
+	switch {
+	case err != nil && l.level >= gormlogger.Error &&
+		!(l.opts.IgnoreRecordNotFoundError && errors.Is(err, gorm.ErrRecordNotFound)):
+		zapLevel, msg = zapcore.ErrorLevel, "sql error"
+	case l.opts.SlowThreshold > 0 && elapsed > l.opts.SlowThreshold && l.level >= gormlogger.Warn:
+		zapLevel, msg = zapcore.WarnLevel, "slow sql"
+	case l.level >= gormlogger.Info:
+		zapLevel, msg = zapcore.DebugLevel, "sql"
+	default:
+		return
+	}
+
+	// 级别未开启时不生成 SQL
+	ce := l.zl.Check(zapLevel, msg)
+	if ce == nil {
+		return
+	}
+	sql, rows := fc()
+	fields := append(Fields(ctx),
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Float64("elapsed_ms", float64(elapsed)/1e6),
+		zap.String("source", utils.FileWithLineNum()),
+	)
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	if zapLevel == zapcore.WarnLevel {
+		fields = append(fields, zap.Duration("threshold", l.opts.SlowThreshold))
+	}
+	ce.Write(fields...)
+}
+
+// ParamsFilter 实现 gorm.ParamsFilter，开启 RedactParams 时不把参数拼进 SQL
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.opts.RedactParams {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// log 输出 GORM 的普通日志
+func (l *GormLogger) log(ctx context.Context, gormLevel gormlogger.LogLevel, zapLevel zapcore.Level, msg string) {
+	if l.level < gormLevel {
+		return
+	}
+	if ce := l.zl.Check(zapLevel, msg); ce != nil {
+		ce.Write(append(Fields(ctx), zap.String("source", utils.FileWithLineNum()))...)
+	}
+}
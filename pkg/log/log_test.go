@@ -0,0 +1,119 @@
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"helloworld/pkg/rpcctx"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observe 把输出替换为内存 core，测试结束后恢复
+func observe(t *testing.T, p Policy) *observer.ObservedLogs {
+	t.Helper()
+	saved := current.Load()
+	t.Cleanup(func() { current.Store(saved) })
+	sink, logs := observer.New(zapcore.DebugLevel)
+	swap(sink, p)
+	return logs
+}
+
+func TestFromContextAddsFieldsAtWrite(t *testing.T) {
+	logs := observe(t, Policy{Level: zapcore.InfoLevel})
+	ctx := rpcctx.WithRequestID(context.Background(), "req-1")
+
+	FromContext(ctx).Debugf("dropped")
+	FromContext(ctx).With("k", "v").Infof("hello %s", "world")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Message != "hello world" {
+		t.Errorf("message = %q", e.Message)
+	}
+	fields := e.ContextMap()
+	if fields[RequestIDKey] != "req-1" || fields["k"] != "v" {
+		t.Errorf("fields = %v, want request_id and k", fields)
+	}
+	if !e.Caller.Defined {
+		t.Error("caller is not set")
+	}
+}
+
+func TestFromContextAppliesPolicy(t *testing.T) {
+	logs := observe(t, Policy{
+		Level:    zapcore.DebugLevel,
+		Levels:   map[string]zapcore.Level{"quiet": zapcore.ErrorLevel},
+		Sampling: &SamplingPolicy{Initial: 2, Thereafter: 0},
+	})
+	ctx := rpcctx.WithRequestID(context.Background(), "req-1")
+
+	// 采样状态在请求之间共享
+	for i := 0; i < 5; i++ {
+		FromContext(ctx).Infof("repeated")
+	}
+	if n := logs.FilterMessage("repeated").Len(); n != 2 {
+		t.Errorf("sampled entries = %d, want 2", n)
+	}
+
+	quiet := root.Named("quiet").WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return c.(*dynamicCore).withContext(Fields(ctx))
+	}))
+	quiet.Warn("filtered")
+	if n := logs.FilterMessage("filtered").Len(); n != 0 {
+		t.Errorf("entries below the logger level = %d, want 0", n)
+	}
+}
+
+func TestConfigureAppliesToCreatedLoggers(t *testing.T) {
+	logs := observe(t, Policy{Level: zapcore.ErrorLevel})
+	l := FromContext(rpcctx.WithRequestID(context.Background(), "req-1"))
+	named := Named("helloworld/pkg/x").With("k", "v")
+
+	l.Info("before")
+	named.Info("before")
+	Configure(Policy{Level: zapcore.InfoLevel})
+	l.Info("after")
+	named.Info("after")
+
+	if n := logs.FilterMessage("before").Len(); n != 0 {
+		t.Errorf("entries before Configure = %d, want 0", n)
+	}
+	after := logs.FilterMessage("after").AllUntimed()
+	if len(after) != 2 {
+		t.Fatalf("entries after Configure = %d, want 2", len(after))
+	}
+	if after[1].ContextMap()["k"] != "v" {
+		t.Errorf("named logger fields = %v", after[1].ContextMap())
+	}
+}
+
+func TestConfigureKeepsErrorLimiter(t *testing.T) {
+	limit := &RateLimitPolicy{Interval: time.Hour, Burst: 1}
+	logs := observe(t, Policy{Level: zapcore.InfoLevel, ErrorRateLimit: limit})
+	l := Named("helloworld/pkg/x")
+
+	l.Error("redis down")
+	l.Error("redis down")
+	// 只改级别：限频计数保留
+	Configure(Policy{Level: zapcore.DebugLevel, ErrorRateLimit: &RateLimitPolicy{Interval: time.Hour, Burst: 1}})
+	l.Error("redis down")
+	if n := logs.FilterMessage("redis down").Len(); n != 1 {
+		t.Errorf("entries after level change = %d, want 1", n)
+	}
+
+	// 限频参数变化：重新计数
+	Configure(Policy{Level: zapcore.DebugLevel, ErrorRateLimit: &RateLimitPolicy{Interval: time.Hour, Burst: 2}})
+	l.Error("redis down")
+	l.Error("redis down")
+	l.Error("redis down")
+	if n := logs.FilterMessage("redis down").Len(); n != 3 {
+		t.Errorf("entries after limit change = %d, want 3", n)
+	}
+}
@@ -0,0 +1,109 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// logfmtPool logfmt 编码输出缓冲池
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder 输出 key=value 格式的日志，例如
+// time=2024-01-01T00:00:00.000Z level=info logger=gorm caller=x.go:1 msg="hello" trace_id=abc
+type logfmtEncoder struct {
+	*zapcore.MapObjectEncoder
+	cfg zapcore.EncoderConfig
+}
+
+// NewLogfmtEncoder 创建 logfmt encoder，时间、级别等使用 cfg 中的 key，其余字段按字母序输出
+func NewLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), cfg: cfg}
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := &logfmtEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), cfg: e.cfg}
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
+	}
+	return clone
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	// 本条日志的字段写入克隆出的 encoder，不影响 With 添加的字段
+	enc := e.Clone().(*logfmtEncoder)
+	for _, f := range fields {
+		f.AddTo(enc.MapObjectEncoder)
+	}
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := logfmtPool.Get()
+	if e.cfg.TimeKey != "" {
+		appendPair(buf, e.cfg.TimeKey, ent.Time.Format("2006-01-02T15:04:05.000Z0700"))
+	}
+	if e.cfg.LevelKey != "" {
+		appendPair(buf, e.cfg.LevelKey, ent.Level.String())
+	}
+	if e.cfg.NameKey != "" && ent.LoggerName != "" {
+		appendPair(buf, e.cfg.NameKey, ent.LoggerName)
+	}
+	if e.cfg.CallerKey != "" && ent.Caller.Defined {
+		appendPair(buf, e.cfg.CallerKey, ent.Caller.TrimmedPath())
+	}
+	if e.cfg.MessageKey != "" {
+		appendPair(buf, e.cfg.MessageKey, ent.Message)
+	}
+	for _, k := range keys {
+		appendPair(buf, k, formatValue(enc.Fields[k]))
+	}
+	if e.cfg.StacktraceKey != "" && ent.Stack != "" {
+		appendPair(buf, e.cfg.StacktraceKey, ent.Stack)
+	}
+	buf.AppendString(zapcore.DefaultLineEnding)
+	return buf, nil
+}
+
+// appendPair 追加一个 key=value，值包含空格、引号或等号时加引号
+func appendPair(buf *buffer.Buffer, key, value string) {
+	if buf.Len() > 0 {
+		buf.AppendByte(' ')
+	}
+	buf.AppendString(key)
+	buf.AppendByte('=')
+	if value == "" || strings.ContainsAny(value, " =\"\t\r\n") {
+		buf.AppendString(fmt.Sprintf("%q", value))
+		return
+	}
+	buf.AppendString(value)
+}
+
+// formatValue 将字段值转换为字符串，数字和复合类型使用 JSON
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case time.Duration:
+		return val.String()
+	case fmt.Stringer:
+		return val.String()
+	case error:
+		return val.Error()
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
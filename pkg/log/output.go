@@ -0,0 +1,290 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 输出类型
+const (
+	OutputStdout       = "stdout"
+	OutputStderr       = "stderr"
+	OutputFile         = "file"          // 普通文件，追加写入，不轮转
+	OutputRotatingFile = "rotating_file" // 按大小/天数轮转的文件
+	OutputSyslog       = "syslog"        // syslog，Address 为空时写本机 syslog
+	OutputUDP          = "udp"           // 每条日志一个 UDP 包
+)
+
+// 编码格式
+const (
+	EncoderJSON    = "json"
+	EncoderConsole = "console"
+	EncoderLogfmt  = "logfmt"
+)
+
+// Output 一个日志输出目标
+type Output struct {
+	Type     string        // 输出类型
+	Encoder  string        // 编码格式，默认 stdout/stderr 为 console，其余为 json
+	Level    zapcore.Level // 该输出的级别下限
+	Filename string        // file / rotating_file 的文件路径
+	Network  string        // syslog 网络类型：udp、tcp，为空时写本机 syslog
+	Address  string        // syslog / udp 地址
+	Tag      string        // syslog tag
+	Rotation Rotation      // rotating_file 轮转策略
+}
+
+// Rotation 文件轮转策略
+type Rotation struct {
+	MaxSize    int  // 单个文件最大大小(MB)，默认 100
+	MaxAge     int  // 保留天数，0 表示不按天数清理
+	MaxBackups int  // 最多保留的备份文件数，0 表示不限制
+	Compress   bool // 是否 gzip 压缩旧文件
+	Daily      bool // 是否每天零点轮转一次
+	LocalTime  bool // 备份文件名和零点使用本地时间
+}
+
+// EncoderConfig 日志输出默认使用的 encoder 配置
+func EncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// NewOutputs 按输出列表创建合并后的 core，返回的 close 函数用于关闭文件、连接和轮转定时器
+// close 会等待正在进行的写入完成后再关闭，之后经由旧 core 的写入直接丢弃，
+// 因此替换输出时先 SetOutput 发布新的 core，再关闭旧的输出
+func NewOutputs(outputs []Output) (zapcore.Core, func() error, error) {
+	if len(outputs) == 0 {
+		return nil, nil, errors.New("log: no outputs configured")
+	}
+
+	g := &outputGuard{}
+	cores := make([]zapcore.Core, 0, len(outputs))
+	closers := make([]func() error, 0, len(outputs))
+	closeAll := func() error {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.closed {
+			return nil
+		}
+		g.closed = true
+
+		var errs []error
+		for _, c := range closers {
+			if err := c(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	for i, o := range outputs {
+		core, closer, err := newOutput(o, g)
+		if err != nil {
+			_ = closeAll()
+			return nil, nil, fmt.Errorf("log: output[%d] %s: %w", i, o.Type, err)
+		}
+		cores = append(cores, core)
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+	return zapcore.NewTee(cores...), closeAll, nil
+}
+
+// newOutput 创建单个输出，需要关闭的输出通过 g 保护写入
+func newOutput(o Output, g *outputGuard) (zapcore.Core, func() error, error) {
+	enc, err := newEncoder(o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch o.Type {
+	case OutputStdout:
+		return zapcore.NewCore(enc, zapcore.Lock(os.Stdout), o.Level), nil, nil
+	case OutputStderr:
+		return zapcore.NewCore(enc, zapcore.Lock(os.Stderr), o.Level), nil, nil
+	case OutputFile:
+		if err := ensureDir(o.Filename); err != nil {
+			return nil, nil, err
+		}
+		f, err := os.OpenFile(o.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zapcore.NewCore(enc, g.syncer(zapcore.Lock(f)), o.Level), f.Close, nil
+	case OutputRotatingFile:
+		if err := ensureDir(o.Filename); err != nil {
+			return nil, nil, err
+		}
+		w := newRotatingWriter(o.Filename, o.Rotation)
+		return zapcore.NewCore(enc, g.syncer(zapcore.AddSync(w)), o.Level), w.Close, nil
+	case OutputSyslog:
+		return newSyslogOutput(o, enc, g)
+	case OutputUDP:
+		if o.Address == "" {
+			return nil, nil, errors.New("address is required")
+		}
+		conn, err := net.Dial("udp", o.Address)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zapcore.NewCore(enc, g.syncer(zapcore.Lock(zapcore.AddSync(udpWriter{conn}))), o.Level), conn.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown output type %q", o.Type)
+	}
+}
+
+// newEncoder 按输出配置创建 encoder
+func newEncoder(o Output) (zapcore.Encoder, error) {
+	name := o.Encoder
+	if name == "" {
+		name = EncoderJSON
+		if o.Type == OutputStdout || o.Type == OutputStderr {
+			name = EncoderConsole
+		}
+	}
+
+	cfg := EncoderConfig()
+	switch strings.ToLower(name) {
+	case EncoderJSON:
+		return zapcore.NewJSONEncoder(cfg), nil
+	case EncoderConsole:
+		return zapcore.NewConsoleEncoder(cfg), nil
+	case EncoderLogfmt:
+		cfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+		return NewLogfmtEncoder(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown encoder %q", name)
+	}
+}
+
+// ensureDir 确保日志目录存在
+func ensureDir(filename string) error {
+	if filename == "" {
+		return errors.New("filename is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	return nil
+}
+
+// rotatingWriter lumberjack 轮转文件，可选每天零点额外轮转一次
+type rotatingWriter struct {
+	*lumberjack.Logger
+	stop chan struct{}
+}
+
+func newRotatingWriter(filename string, r Rotation) *rotatingWriter {
+	if r.MaxSize <= 0 {
+		r.MaxSize = 100
+	}
+	w := &rotatingWriter{
+		Logger: &lumberjack.Logger{
+			Filename:   filename,
+			MaxSize:    r.MaxSize,
+			MaxAge:     r.MaxAge,
+			MaxBackups: r.MaxBackups,
+			Compress:   r.Compress,
+			LocalTime:  r.LocalTime,
+		},
+		stop: make(chan struct{}),
+	}
+	if r.Daily {
+		go w.rotateDaily(r.LocalTime)
+	}
+	return w
+}
+
+// rotateDaily 每天零点轮转
+func (w *rotatingWriter) rotateDaily(local bool) {
+	for {
+		now := time.Now()
+		if !local {
+			now = now.UTC()
+		}
+		next := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-w.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := w.Rotate(); err != nil {
+				fmt.Fprintf(os.Stderr, "log: daily rotate %s failed: %v\n", w.Filename, err)
+			}
+		}
+	}
+}
+
+// Close 停止零点轮转并关闭文件
+func (w *rotatingWriter) Close() error {
+	close(w.stop)
+	return w.Logger.Close()
+}
+
+// udpWriter 忽略发送错误，接收端未启动时不影响业务也不刷屏
+type udpWriter struct {
+	conn net.Conn
+}
+
+func (w udpWriter) Write(p []byte) (int, error) {
+	_, _ = w.conn.Write(p)
+	return len(p), nil
+}
+
+// outputGuard 同一次 NewOutputs 创建的输出共用，关闭时等待正在进行的写入完成
+type outputGuard struct {
+	mu     sync.RWMutex
+	closed bool
+}
+
+// syncer 包装需要关闭的 WriteSyncer
+func (g *outputGuard) syncer(ws zapcore.WriteSyncer) zapcore.WriteSyncer {
+	return &guardedSyncer{ws: ws, g: g}
+}
+
+// guardedSyncer 写入期间持有读锁，输出关闭后丢弃写入
+type guardedSyncer struct {
+	ws zapcore.WriteSyncer
+	g  *outputGuard
+}
+
+func (w *guardedSyncer) Write(p []byte) (int, error) {
+	w.g.mu.RLock()
+	defer w.g.mu.RUnlock()
+	if w.g.closed {
+		return len(p), nil
+	}
+	return w.ws.Write(p)
+}
+
+func (w *guardedSyncer) Sync() error {
+	w.g.mu.RLock()
+	defer w.g.mu.RUnlock()
+	if w.g.closed {
+		return nil
+	}
+	return w.ws.Sync()
+}
@@ -0,0 +1,79 @@
+//go:build !windows
+
+package log
+
+import (
+	"log/syslog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newSyslogOutput 创建 syslog 输出，Network 和 Address 为空时写本机 syslog
+func newSyslogOutput(o Output, enc zapcore.Encoder, g *outputGuard) (zapcore.Core, func() error, error) {
+	tag := o.Tag
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+	w, err := syslog.Dial(o.Network, o.Address, syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &syslogCore{LevelEnabler: o.Level, enc: enc, w: w, g: g}, w.Close, nil
+}
+
+// syslogCore 按日志级别映射 syslog 优先级
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	w   *syslog.Writer
+	g   *outputGuard
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, w: c.w, g: c.g}
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+
+	c.g.mu.RLock()
+	defer c.g.mu.RUnlock()
+	if c.g.closed {
+		return nil
+	}
+	switch ent.Level {
+	case zapcore.DebugLevel:
+		return c.w.Debug(msg)
+	case zapcore.InfoLevel:
+		return c.w.Info(msg)
+	case zapcore.WarnLevel:
+		return c.w.Warning(msg)
+	case zapcore.ErrorLevel:
+		return c.w.Err(msg)
+	default:
+		return c.w.Crit(msg)
+	}
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package log
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newSyslogOutput Windows 没有 syslog
+func newSyslogOutput(Output, zapcore.Encoder, *outputGuard) (zapcore.Core, func() error, error) {
+	return nil, nil, errors.New("syslog output is unsupported on this platform")
+}
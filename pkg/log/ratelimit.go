@@ -0,0 +1,83 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxRateLimitKeys 限频表最多记录的消息数，超过后清空，避免消息中带变量时无限增长
+const maxRateLimitKeys = 10000
+
+// rateLimitCore 对 error 及以上级别的重复日志限频，例如 Redis 不可用时反复出现的连接失败
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *errorLimiter
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < zapcore.ErrorLevel {
+		return c.Core.Check(ent, ce)
+	}
+	allowed, suppressed := c.limiter.allow(ent.LoggerName+"\x00"+ent.Message, ent.Time)
+	if !allowed {
+		return ce
+	}
+	if suppressed > 0 {
+		return c.Core.With([]zapcore.Field{zap.Int("suppressed", suppressed)}).Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// window 单条消息当前窗口的计数
+type window struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+// errorLimiter 固定窗口计数，With 派生的 core 共享同一个 limiter，限频参数不变时 Configure 也沿用
+type errorLimiter struct {
+	interval time.Duration
+	burst    int
+
+	mu      sync.Mutex
+	windows map[string]*window
+}
+
+func newErrorLimiter(p *RateLimitPolicy) *errorLimiter {
+	return &errorLimiter{interval: p.Interval, burst: p.Burst, windows: make(map[string]*window)}
+}
+
+// allow 判断是否输出，返回上一个窗口被丢弃的条数
+func (l *errorLimiter) allow(key string, now time.Time) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	if !ok {
+		if len(l.windows) >= maxRateLimitKeys {
+			l.windows = make(map[string]*window)
+		}
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+
+	suppressed := 0
+	if now.Sub(w.start) >= l.interval {
+		suppressed = w.suppressed
+		w.start, w.count, w.suppressed = now, 0, 0
+	}
+	if w.count >= l.burst {
+		w.suppressed++
+		return false, 0
+	}
+	w.count++
+	return true, suppressed
+}
@@ -0,0 +1,33 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// RedisLoggerName go-redis 日志使用的 logger 名称，可通过 log.levels.redis 或 redis.log_level 调整级别
+const RedisLoggerName = "redis"
+
+// RedisLogger 将 go-redis 内部日志（连接池、重连等）写入 zap，通过 redis.SetLogger 安装
+type RedisLogger struct {
+	zl *zap.Logger
+}
+
+// NewRedisLogger 创建 go-redis 日志适配器
+func NewRedisLogger() *RedisLogger {
+	return &RedisLogger{zl: root.Named(RedisLoggerName).WithOptions(zap.AddCallerSkip(1))}
+}
+
+// Printf 实现 go-redis 的 Logging 接口
+// go-redis 的日志不区分级别，且基本都是连接异常等需要关注的问题，统一按 warn 输出
+func (l *RedisLogger) Printf(ctx context.Context, format string, v ...interface{}) {
+	msg := strings.TrimPrefix(fmt.Sprintf(format, v...), "redis: ")
+	if ctx == nil {
+		l.zl.Warn(msg)
+		return
+	}
+	l.zl.Warn(msg, Fields(ctx)...)
+}
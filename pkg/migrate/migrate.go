@@ -0,0 +1,158 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"helloworld/pkg/log"
+
+	"gorm.io/gorm"
+)
+
+// logger 迁移日志
+var logger = log.Named("helloworld/pkg/migrate")
+
+// lockName 迁移使用的 MySQL 命名锁，保证多副本同时启动时只有一个实例执行迁移
+const lockName = "helloworld_schema_migrations"
+
+// lockTimeout 等待命名锁的最长时间（秒）
+const lockTimeout = 30
+
+// Migration 一个版本化的 schema 变更
+type Migration struct {
+	Version int64                   // 版本号，必须唯一且单调递增，建议使用 YYYYMMDDHHMM 格式
+	Name    string                  // 变更描述
+	Up      func(tx *gorm.DB) error // 执行变更
+}
+
+// SchemaMigration 已执行迁移的记录表
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定迁移记录表名
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Run 按版本号顺序执行尚未执行的迁移
+// 注意：MySQL 的 DDL 会隐式提交事务，所以每个 Migration 的 Up 应尽量只包含一条 DDL
+func Run(ctx context.Context, db *gorm.DB, migrations []Migration) error {
+	if db == nil {
+		return fmt.Errorf("migrate: db is nil")
+	}
+
+	sorted, err := sortMigrations(migrations)
+	if err != nil {
+		return err
+	}
+
+	// 命名锁是连接级别的，必须在同一个连接上加锁、执行和解锁
+	return db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := acquireLock(conn); err != nil {
+			return err
+		}
+		defer releaseLock(conn)
+
+		if err := conn.AutoMigrate(&SchemaMigration{}); err != nil {
+			return fmt.Errorf("migrate: failed to create schema_migrations table: %w", err)
+		}
+
+		var applied []SchemaMigration
+		if err := conn.Find(&applied).Error; err != nil {
+			return fmt.Errorf("migrate: failed to load applied migrations: %w", err)
+		}
+		appliedSet := make(map[int64]bool, len(applied))
+		for _, m := range applied {
+			appliedSet[m.Version] = true
+		}
+
+		newlyApplied := 0
+		for _, m := range sorted {
+			if appliedSet[m.Version] {
+				continue
+			}
+			newlyApplied++
+
+			logger.Infof("Applying migration %d: %s", m.Version, m.Name)
+			start := time.Now()
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{
+					Version:   m.Version,
+					Name:      m.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migrate: migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+			logger.Infof("Migration %d applied in %v", m.Version, time.Since(start))
+		}
+
+		// 失败时直接返回错误，走到这里时已没有待执行的迁移
+		logger.Infof("Schema migrations done: total=%d, already_applied=%d, newly_applied=%d",
+			len(sorted), len(applied), newlyApplied)
+		return nil
+	})
+}
+
+// AddIndex 索引不存在时创建，用于可重复执行的迁移：
+// DDL 隐式提交，迁移在建索引之后、写入迁移记录之前失败时，重试不会因索引已存在而失败
+func AddIndex(tx *gorm.DB, table, index, columns string) error {
+	var n int64
+	err := tx.Raw(`SELECT COUNT(*) FROM information_schema.statistics
+WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?`, table, index).Scan(&n).Error
+	if err != nil {
+		return fmt.Errorf("migrate: failed to check index %s.%s: %w", table, index, err)
+	}
+	if n > 0 {
+		logger.Infof("Index %s.%s already exists, skipped", table, index)
+		return nil
+	}
+	return tx.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD INDEX `%s` (%s)", table, index, columns)).Error
+}
+
+// sortMigrations 按版本排序并检查重复版本号
+func sortMigrations(migrations []Migration) ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, m := range sorted {
+		if m.Up == nil {
+			return nil, fmt.Errorf("migrate: migration %d (%s) has no Up func", m.Version, m.Name)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migrate: duplicate migration version %d", m.Version)
+		}
+	}
+	return sorted, nil
+}
+
+// acquireLock 获取 MySQL 命名锁
+func acquireLock(conn *gorm.DB) error {
+	var got int
+	if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&got).Error; err != nil {
+		return fmt.Errorf("migrate: failed to acquire lock: %w", err)
+	}
+	if got != 1 {
+		return fmt.Errorf("migrate: timeout waiting for lock %s", lockName)
+	}
+	return nil
+}
+
+// releaseLock 释放 MySQL 命名锁
+func releaseLock(conn *gorm.DB) {
+	if err := conn.Exec("SELECT RELEASE_LOCK(?)", lockName).Error; err != nil {
+		logger.Errorf("migrate: failed to release lock: %v", err)
+	}
+}
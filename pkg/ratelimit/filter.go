@@ -0,0 +1,166 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"helloworld/pkg/config"
+	"helloworld/pkg/log"
+	"helloworld/pkg/rpcctx"
+
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"github.com/redis/go-redis/v9"
+)
+
+// logger 限流日志
+var logger = log.Named("helloworld/pkg/ratelimit")
+
+// FilterKey provider 限流 filter 名称，通过 server.WithServerFilter 启用
+const FilterKey = "ratelimit"
+
+// RetryAfterKey 被拒绝时返回的重试等待秒数
+const RetryAfterKey = "retry-after"
+
+// defaultCaller 未携带 caller-app 的调用方，同时也是调用方默认规则的名称
+const defaultCaller = "default"
+
+// redisTimeout 限流检查的 Redis 超时，超时后降级到进程内限流
+const redisTimeout = 50 * time.Millisecond
+
+var (
+	limiter       atomic.Pointer[fallbackLimiter]
+	currentConfig atomic.Pointer[config.RateLimitConfig]
+)
+
+func init() {
+	extension.SetFilter(FilterKey, newFilter)
+}
+
+// Setup 初始化限流器并监听配置变化，client 为 nil 时使用进程内限流
+func Setup(client *redis.Client) {
+	// Redis 故障期间每个请求都会降级，警告每 10 秒最多输出一次
+	throttle := &logThrottle{interval: 10 * time.Second}
+	lim := &fallbackLimiter{
+		fallback: NewLocalLimiter(),
+		onError: func(err error) {
+			if suppressed, ok := throttle.allow(time.Now()); ok {
+				logger.Warnf("ratelimit: redis limiter failed, falling back to local limiter (%d similar errors suppressed): %v",
+					suppressed, err)
+			}
+		},
+	}
+	if client != nil {
+		lim.primary = NewRedisLimiter(client)
+	}
+	limiter.Store(lim)
+
+	reload()
+	config.RegisterChangeListener(func(map[string]interface{}) {
+		reload()
+	})
+}
+
+// reload 重新加载限流规则
+func reload() {
+	cfg := config.GetRateLimitConfigFromDubbo()
+	currentConfig.Store(cfg)
+	logger.Infof("Rate limit config loaded: enabled=%v, methods=%d, callers=%d",
+		cfg.Enabled, len(cfg.Methods), len(cfg.Callers))
+}
+
+type rateLimitFilter struct{}
+
+func newFilter() filter.Filter {
+	return &rateLimitFilter{}
+}
+
+// Invoke 先检查调用方级限流，再检查方法级限流
+// 被调用方规则拒绝的请求不消耗方法级的共享配额，避免一个被限流的调用方耗尽所有调用方的方法配额
+func (f *rateLimitFilter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	cfg := currentConfig.Load()
+	lim := limiter.Load()
+	if cfg == nil || !cfg.Enabled || lim == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	service := invoker.GetURL().Service()
+	method := invocation.MethodName()
+	caller := rpcctx.InvocationAttachment(invocation, rpcctx.CallerAppKey)
+	if caller == "" {
+		caller = defaultCaller
+	}
+
+	if rule, ok := callerRule(cfg, caller); ok {
+		key := fmt.Sprintf("%sc:%s:%s.%s", cfg.Prefix, caller, service, method)
+		if res := check(ctx, lim, key, rule, service, method, caller); res != nil {
+			return res
+		}
+	}
+	if rule, ok := methodRule(cfg, service, method); ok {
+		key := fmt.Sprintf("%sm:%s.%s", cfg.Prefix, service, method)
+		if res := check(ctx, lim, key, rule, service, method, caller); res != nil {
+			return res
+		}
+	}
+
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse 直接返回结果
+func (f *rateLimitFilter) OnResponse(_ context.Context, result result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return result
+}
+
+// check 检查单条规则，被拒绝时返回带 ResourceExhausted 错误的结果
+func check(ctx context.Context, lim *fallbackLimiter, key string, rule config.RateLimitRule,
+	service, method, caller string) result.Result {
+	checkCtx, cancel := context.WithTimeout(ctx, redisTimeout)
+	allowed, retryAfter, err := lim.Allow(checkCtx, key, rule)
+	cancel()
+	if err != nil {
+		// 限流器本身故障时放行
+		log.FromContext(ctx).Errorf("ratelimit: check %s failed: %v", key, err)
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+
+	seconds := strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds()))))
+	log.FromContext(ctx).Warnf("ratelimit: rejected %s.%s from %s, retry after %ss", service, method, caller, seconds)
+
+	triErr := triple_protocol.NewError(triple_protocol.CodeResourceExhausted,
+		fmt.Errorf("rate limit exceeded for %s.%s, retry after %ss", service, method, seconds))
+	triErr.Meta().Set(RetryAfterKey, seconds)
+
+	res := &result.RPCResult{}
+	res.SetError(triErr)
+	res.AddAttachment(RetryAfterKey, seconds)
+	return res
+}
+
+// methodRule 查找方法级规则，"接口名.方法名" 优先于 "方法名"
+func methodRule(cfg *config.RateLimitConfig, service, method string) (config.RateLimitRule, bool) {
+	if rule, ok := cfg.Methods[service+"."+method]; ok {
+		return rule, true
+	}
+	rule, ok := cfg.Methods[method]
+	return rule, ok
+}
+
+// callerRule 查找调用方规则，未单独配置时使用 default 规则
+func callerRule(cfg *config.RateLimitConfig, caller string) (config.RateLimitRule, bool) {
+	if rule, ok := cfg.Callers[caller]; ok {
+		return rule, true
+	}
+	rule, ok := cfg.Callers[defaultCaller]
+	return rule, ok
+}
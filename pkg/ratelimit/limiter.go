@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"helloworld/pkg/config"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// Limiter 限流器
+type Limiter interface {
+	// Allow 尝试获取一个令牌，被拒绝时返回建议的重试等待时间
+	Allow(ctx context.Context, key string, rule config.RateLimitRule) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// tokenBucketScript Redis 令牌桶，使用 Redis 服务端时间避免各副本时钟偏差
+// KEYS[1] 桶 key，ARGV[1] 每秒令牌数，ARGV[2] 桶容量
+// 返回 {是否允许, 重试等待毫秒数}
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local data = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, retry}
+`)
+
+// redisLimiter 基于 Redis 的令牌桶，所有副本共享
+type redisLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisLimiter 创建 Redis 令牌桶限流器
+func NewRedisLimiter(client *redis.Client) Limiter {
+	return &redisLimiter{client: client}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, rule config.RateLimitRule) (bool, time.Duration, error) {
+	res, err := tokenBucketScript.Run(ctx, l.client, []string{key}, rule.Rate, rule.Burst).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("ratelimit: unexpected script result %v", res)
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// 进程内令牌桶的数量限制：key 中的 caller-app 由调用方填写，不加限制时任意调用方名称都会新增一个桶
+const (
+	localIdleTTL       = 10 * time.Minute // 超过该时间未使用的桶被清理，重新创建的桶是满的，与长时间空闲的桶等价
+	localSweepInterval = time.Minute      // 清理的最小间隔
+	localMaxBuckets    = 10000            // 桶数上限，达到上限后新的 key 按规则共用溢出桶
+)
+
+// localLimiter 进程内令牌桶，Redis 不可用时使用，只限制当前副本
+type localLimiter struct {
+	mu        sync.Mutex
+	limiters  map[string]*localBucket
+	lastSweep time.Time
+}
+
+// localBucket 记录规则，规则变化时重建
+type localBucket struct {
+	rule     config.RateLimitRule
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// NewLocalLimiter 创建进程内限流器
+func NewLocalLimiter() Limiter {
+	return &localLimiter{limiters: make(map[string]*localBucket), lastSweep: time.Now()}
+}
+
+func (l *localLimiter) Allow(_ context.Context, key string, rule config.RateLimitRule) (bool, time.Duration, error) {
+	now := time.Now()
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) >= localSweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.limiters[key]
+	if !ok && len(l.limiters) >= localMaxBuckets {
+		key = fmt.Sprintf("overflow:%g:%d", rule.Rate, rule.Burst)
+		b, ok = l.limiters[key]
+	}
+	if !ok || b.rule != rule {
+		b = &localBucket{rule: rule, limiter: rate.NewLimiter(rate.Limit(rule.Rate), rule.Burst)}
+		l.limiters[key] = b
+	}
+	b.lastUsed = now
+	l.mu.Unlock()
+
+	r := b.limiter.Reserve()
+	if !r.OK() {
+		return false, time.Second, nil
+	}
+	delay := r.Delay()
+	if delay == 0 {
+		return true, 0, nil
+	}
+	// 不等待，归还令牌并告知重试时间
+	r.Cancel()
+	return false, time.Duration(math.Ceil(float64(delay)/float64(time.Millisecond))) * time.Millisecond, nil
+}
+
+// sweep 清理空闲的桶，调用方持有 mu
+func (l *localLimiter) sweep(now time.Time) {
+	for key, b := range l.limiters {
+		if now.Sub(b.lastUsed) >= localIdleTTL {
+			delete(l.limiters, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// logThrottle 限制重复日志的频率，每个 interval 最多输出一次
+type logThrottle struct {
+	interval time.Duration
+
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+// allow 是否输出本次日志，输出时返回上次输出之后被抑制的次数
+func (t *logThrottle) allow(now time.Time) (suppressed int, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.last.IsZero() && now.Sub(t.last) < t.interval {
+		t.suppressed++
+		return 0, false
+	}
+	suppressed, t.suppressed = t.suppressed, 0
+	t.last = now
+	return suppressed, true
+}
+
+// fallbackLimiter 优先使用 Redis，出错时降级到进程内限流
+type fallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+	onError  func(err error)
+}
+
+func (l *fallbackLimiter) Allow(ctx context.Context, key string, rule config.RateLimitRule) (bool, time.Duration, error) {
+	if l.primary != nil {
+		allowed, retry, err := l.primary.Allow(ctx, key, rule)
+		if err == nil {
+			return allowed, retry, nil
+		}
+		if l.onError != nil {
+			l.onError(err)
+		}
+	}
+	return l.fallback.Allow(ctx, key, rule)
+}
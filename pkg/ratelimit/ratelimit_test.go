@@ -0,0 +1,239 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"helloworld/pkg/config"
+	"helloworld/pkg/rpcctx"
+
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisTokenBucket(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	lim := NewRedisLimiter(client)
+	ctx := context.Background()
+	rule := config.RateLimitRule{Rate: 1, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		if allowed, _, err := lim.Allow(ctx, "rl:k", rule); err != nil || !allowed {
+			t.Fatalf("request %d: Allow() = %v, %v, want allowed", i, allowed, err)
+		}
+	}
+	allowed, retry, err := lim.Allow(ctx, "rl:k", rule)
+	if err != nil || allowed {
+		t.Fatalf("Allow() over burst = %v, %v, want rejected", allowed, err)
+	}
+	if retry <= 0 || retry > time.Second {
+		t.Errorf("retry after = %v, want (0, 1s]", retry)
+	}
+	// 桶 key 带过期时间，空闲后自动删除
+	if ttl := mr.TTL("rl:k"); ttl <= 0 {
+		t.Errorf("bucket ttl = %v, want positive", ttl)
+	}
+
+	// 不同 key 独立计数
+	if allowed, _, _ := lim.Allow(ctx, "rl:other", rule); !allowed {
+		t.Error("other key was rejected")
+	}
+}
+
+func TestFallbackToLocalWhenRedisDown(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	var errs int
+	lim := &fallbackLimiter{
+		primary:  NewRedisLimiter(client),
+		fallback: NewLocalLimiter(),
+		onError:  func(error) { errs++ },
+	}
+	rule := config.RateLimitRule{Rate: 0.1, Burst: 1}
+
+	if allowed, _, err := lim.Allow(context.Background(), "k", rule); err != nil || !allowed {
+		t.Fatalf("Allow() = %v, %v, want allowed by the local limiter", allowed, err)
+	}
+	if allowed, _, _ := lim.Allow(context.Background(), "k", rule); allowed {
+		t.Error("second Allow() was allowed, want the local limiter to reject")
+	}
+	if errs != 2 {
+		t.Errorf("onError calls = %d, want 2", errs)
+	}
+}
+
+func TestLocalLimiterBucketBound(t *testing.T) {
+	lim := NewLocalLimiter().(*localLimiter)
+	ctx := context.Background()
+	rule := config.RateLimitRule{Rate: 0.1, Burst: 1}
+
+	for i := 0; i < localMaxBuckets; i++ {
+		lim.Allow(ctx, fmt.Sprintf("caller-%d", i), rule)
+	}
+	// 达到上限后新的 key 共用溢出桶
+	if allowed, _, _ := lim.Allow(ctx, "new-1", rule); !allowed {
+		t.Error("first overflow request was rejected")
+	}
+	if allowed, _, _ := lim.Allow(ctx, "new-2", rule); allowed {
+		t.Error("second overflow request was allowed, want it to share the overflow bucket")
+	}
+	if n := len(lim.limiters); n != localMaxBuckets+1 {
+		t.Errorf("buckets = %d, want %d", n, localMaxBuckets+1)
+	}
+
+	// 空闲的桶在下一次清理时删除
+	idle := time.Now().Add(-localIdleTTL)
+	for _, b := range lim.limiters {
+		b.lastUsed = idle
+	}
+	lim.lastSweep = time.Now().Add(-localSweepInterval)
+	lim.Allow(ctx, "caller-0", rule)
+	if n := len(lim.limiters); n != 1 {
+		t.Errorf("buckets after sweep = %d, want 1", n)
+	}
+}
+
+func TestLocalLimiterRuleChange(t *testing.T) {
+	lim := NewLocalLimiter()
+	ctx := context.Background()
+
+	lim.Allow(ctx, "k", config.RateLimitRule{Rate: 0.1, Burst: 1})
+	if allowed, _, _ := lim.Allow(ctx, "k", config.RateLimitRule{Rate: 0.1, Burst: 1}); allowed {
+		t.Fatal("second request was allowed")
+	}
+	// 规则变化后重建桶
+	if allowed, _, _ := lim.Allow(ctx, "k", config.RateLimitRule{Rate: 0.1, Burst: 2}); !allowed {
+		t.Error("request after rule change was rejected")
+	}
+}
+
+// countingInvoker 记录调用次数
+type countingInvoker struct {
+	*base.BaseInvoker
+	calls int
+}
+
+func (i *countingInvoker) Invoke(context.Context, base.Invocation) result.Result {
+	i.calls++
+	return &result.RPCResult{}
+}
+
+// useRules 设置限流规则，使用进程内限流
+func useRules(t *testing.T, cfg *config.RateLimitConfig) {
+	t.Helper()
+	cfg.Enabled = true
+	currentConfig.Store(cfg)
+	limiter.Store(&fallbackLimiter{fallback: NewLocalLimiter()})
+	t.Cleanup(func() {
+		currentConfig.Store(nil)
+		limiter.Store(nil)
+	})
+}
+
+func newInvoker(t *testing.T) *countingInvoker {
+	t.Helper()
+	url, err := common.NewURL("tri://127.0.0.1:20000/greet.GreetService?interface=greet.GreetService")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &countingInvoker{BaseInvoker: base.NewBaseInvoker(url)}
+}
+
+// invokeAs 以指定调用方调用 Greet
+func invokeAs(inv base.Invoker, caller string) result.Result {
+	attachments := map[string]interface{}{}
+	if caller != "" {
+		attachments[rpcctx.CallerAppKey] = caller
+	}
+	return newFilter().Invoke(context.Background(), inv, invocation.NewRPCInvocation("Greet", nil, attachments))
+}
+
+func TestCallerCheckedBeforeMethod(t *testing.T) {
+	useRules(t, &config.RateLimitConfig{
+		Methods: map[string]config.RateLimitRule{"greet.GreetService.Greet": {Rate: 0.01, Burst: 3}},
+		Callers: map[string]config.RateLimitRule{"noisy-app": {Rate: 0.01, Burst: 1}},
+	})
+	inv := newInvoker(t)
+
+	// noisy-app 被调用方规则拒绝的请求不消耗方法配额
+	for i := 0; i < 5; i++ {
+		invokeAs(inv, "noisy-app")
+	}
+	if inv.calls != 1 {
+		t.Fatalf("noisy-app calls = %d, want 1", inv.calls)
+	}
+	for i := 0; i < 2; i++ {
+		if res := invokeAs(inv, "go-client"); res.Error() != nil {
+			t.Fatalf("go-client request %d rejected: %v", i, res.Error())
+		}
+	}
+	if res := invokeAs(inv, "go-client"); res.Error() == nil {
+		t.Error("request over the method burst was allowed")
+	}
+	if inv.calls != 3 {
+		t.Errorf("calls = %d, want 3", inv.calls)
+	}
+}
+
+func TestDefaultCallerRule(t *testing.T) {
+	useRules(t, &config.RateLimitConfig{
+		Callers: map[string]config.RateLimitRule{defaultCaller: {Rate: 0.01, Burst: 1}},
+	})
+	inv := newInvoker(t)
+
+	// 未单独配置的调用方按 default 规则各自计数
+	for _, caller := range []string{"a", "b", ""} {
+		if res := invokeAs(inv, caller); res.Error() != nil {
+			t.Errorf("first request from %q rejected: %v", caller, res.Error())
+		}
+		if res := invokeAs(inv, caller); res.Error() == nil {
+			t.Errorf("second request from %q allowed", caller)
+		}
+	}
+}
+
+func TestRejectionCarriesRetryAfter(t *testing.T) {
+	useRules(t, &config.RateLimitConfig{
+		Methods: map[string]config.RateLimitRule{"Greet": {Rate: 0.5, Burst: 1}},
+	})
+	inv := newInvoker(t)
+
+	invokeAs(inv, "go-client")
+	res := invokeAs(inv, "go-client")
+
+	var triErr *triple_protocol.Error
+	if !errors.As(res.Error(), &triErr) || triErr.Code() != triple_protocol.CodeResourceExhausted {
+		t.Fatalf("error = %v, want ResourceExhausted", res.Error())
+	}
+	// 速率 0.5/s，约 2 秒后才有令牌
+	if got := triErr.Meta().Get(RetryAfterKey); got != "2" {
+		t.Errorf("error metadata %s = %q, want 2", RetryAfterKey, got)
+	}
+	if got := res.Attachment(RetryAfterKey, ""); got != "2" {
+		t.Errorf("result attachment %s = %v, want 2", RetryAfterKey, got)
+	}
+}
+
+func TestDisabledPassesThrough(t *testing.T) {
+	useRules(t, &config.RateLimitConfig{
+		Methods: map[string]config.RateLimitRule{"Greet": {Rate: 0.01, Burst: 1}},
+	})
+	currentConfig.Load().Enabled = false
+	inv := newInvoker(t)
+
+	for i := 0; i < 3; i++ {
+		if res := invokeAs(inv, "go-client"); res.Error() != nil {
+			t.Fatalf("request %d rejected while disabled: %v", i, res.Error())
+		}
+	}
+}
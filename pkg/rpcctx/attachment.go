@@ -0,0 +1,113 @@
+package rpcctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 约定的 Triple attachment 键（Triple 会把 header 统一转成小写）
+const (
+	CallerAppKey  = "caller-app"  // 调用方应用名
+	CallerHostKey = "caller-host" // 调用方 IP
+	TraceIDKey    = "trace-id"    // 链路追踪 ID
+	RequestIDKey  = "request-id"  // 请求 ID
+	UserIDKey     = "user-id"     // 终端用户 ID，功能开关按其哈希灰度
+)
+
+// Attachments 获取 context 中的 attachment map，不存在时返回 nil
+func Attachments(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	if m, ok := ctx.Value(constant.AttachmentKey).(map[string]interface{}); ok {
+		return m
+	}
+	return nil
+}
+
+// Attachment 获取单个 attachment 的字符串值
+// provider 端收到的值是 []string，consumer 端设置的值通常是 string，这里统一处理
+func Attachment(ctx context.Context, key string) string {
+	switch v := Attachments(ctx)[key].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// WithAttachment 返回携带指定 attachment 的新 context（consumer 端使用）
+// 会复制已有的 attachment map，避免并发修改调用方持有的 map
+func WithAttachment(ctx context.Context, key, value string) context.Context {
+	old := Attachments(ctx)
+	attachments := make(map[string]interface{}, len(old)+1)
+	for k, v := range old {
+		attachments[k] = v
+	}
+	attachments[key] = value
+	return context.WithValue(ctx, constant.AttachmentKey, attachments)
+}
+
+// CallerApp 获取调用方应用名
+func CallerApp(ctx context.Context) string {
+	return Attachment(ctx, CallerAppKey)
+}
+
+// TraceID 获取链路追踪 ID，优先使用当前 span 的 trace id
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return Attachment(ctx, TraceIDKey)
+}
+
+// requestIDCtxKey 在 provider 端 context 中保存请求 ID
+type requestIDCtxKey struct{}
+
+// WithRequestID 在 context 中保存请求 ID，consumer 端会通过 filter 透传给下游
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// RequestID 获取请求 ID，优先使用 WithRequestID 保存的值，其次是 attachment
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(requestIDCtxKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Attachment(ctx, RequestIDKey)
+}
+
+// NewRequestID 生成请求 ID
+func NewRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// InvocationAttachment 从 invocation 中获取 attachment 的字符串值（filter 中使用）
+func InvocationAttachment(inv base.Invocation, key string) string {
+	switch v := inv.GetAttachmentInterface(key).(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
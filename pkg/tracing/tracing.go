@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"helloworld/pkg/log"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// logger 链路追踪初始化日志
+var logger = log.Named("helloworld/pkg/tracing")
+
+// ScopeName 本项目埋点使用的 instrumentation scope
+const ScopeName = "helloworld"
+
+// Options 链路追踪选项
+type Options struct {
+	ServiceName  string
+	Exporter     string  // otlp | stdout | memory | none
+	Endpoint     string  // OTLP 接收端地址
+	Protocol     string  // OTLP 协议: grpc | http
+	Insecure     bool    // OTLP 是否使用明文连接
+	SamplerRatio float64 // 采样比例(0~1)
+}
+
+// memoryExporter exporter 为 memory 时使用，便于离线测试
+var memoryExporter *tracetest.InMemoryExporter
+
+// Setup 初始化全局 TracerProvider 和 W3C traceparent/baggage 传播器
+// exporter 为 none 时只传播上游的 trace context，不产生 span
+// 返回的 shutdown 用于退出时刷新未导出的 span
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	noop := func(context.Context) error { return nil }
+
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case "", "none":
+		logger.Infof("Tracing disabled, only propagating trace context")
+		return noop, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "memory":
+		memoryExporter = tracetest.NewInMemoryExporter()
+		exporter = memoryExporter
+	case "otlp":
+		exporter, err = newOTLPExporter(ctx, opts)
+	default:
+		return noop, fmt.Errorf("unsupported tracing exporter: %s", opts.Exporter)
+	}
+	if err != nil {
+		return noop, fmt.Errorf("failed to create %s exporter: %w", opts.Exporter, err)
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(opts.ServiceName))
+
+	var processor sdktrace.SpanProcessor
+	if opts.Exporter == "memory" {
+		// 内存导出同步处理，span 结束后立即可见
+		processor = sdktrace.NewSimpleSpanProcessor(exporter)
+	} else {
+		processor = sdktrace.NewBatchSpanProcessor(exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplerRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger.Infof("Tracing initialized: exporter=%s, endpoint=%s, sampler_ratio=%.2f",
+		opts.Exporter, opts.Endpoint, opts.SamplerRatio)
+	return provider.Shutdown, nil
+}
+
+// newOTLPExporter 创建 OTLP exporter
+func newOTLPExporter(ctx context.Context, opts Options) (*otlptrace.Exporter, error) {
+	switch opts.Protocol {
+	case "", "grpc":
+		grpcOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, grpcOpts...)
+	case "http":
+		httpOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			httpOpts = append(httpOpts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, httpOpts...)
+	default:
+		return nil, fmt.Errorf("unsupported otlp protocol: %s", opts.Protocol)
+	}
+}
+
+// MemoryExporter 返回内存 exporter，仅在 exporter 为 memory 时非 nil
+func MemoryExporter() *tracetest.InMemoryExporter {
+	return memoryExporter
+}
+
+// tracer 返回本项目使用的 tracer，每次从全局获取以便 Setup 之后生效
+func tracer() trace.Tracer {
+	return otel.Tracer(ScopeName)
+}
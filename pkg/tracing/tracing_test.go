@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// setupMemory 使用内存 exporter 初始化链路追踪
+func setupMemory(t *testing.T) {
+	t.Helper()
+	shutdown, err := Setup(context.Background(), Options{ServiceName: "tracing-test", Exporter: "memory", SamplerRatio: 1})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+	if MemoryExporter() == nil {
+		t.Fatal("MemoryExporter() = nil")
+	}
+}
+
+func TestInstrumentedCallsRecordChildSpans(t *testing.T) {
+	setupMemory(t)
+
+	// 不可达的 Redis：命令失败也会记录 span
+	rdb := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { _ = rdb.Close() })
+	rdb.AddHook(NewRedisHook("127.0.0.1:1"))
+
+	// DryRun 只生成 SQL，不连接数据库
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:1)/greet",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := db.Use(NewGormPlugin("greet")); err != nil {
+		t.Fatalf("db.Use() error = %v", err)
+	}
+
+	ctx, parent := tracer().Start(context.Background(), "parent")
+	if err := rdb.Get(ctx, "greet:1").Err(); err == nil {
+		t.Fatal("redis GET against closed port succeeded")
+	}
+	var row struct {
+		ID   int64
+		Name string
+	}
+	db.WithContext(ctx).Table("greetings").Where("id = ?", 1).Take(&row)
+	parent.End()
+
+	spans := MemoryExporter().GetSpans()
+	byName := make(map[string]int)
+	for i, s := range spans {
+		byName[s.Name] = i
+	}
+	for _, name := range []string{"parent", "redis get", "gorm.query"} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("span %q not recorded, got %d span(s)", name, len(spans))
+		}
+	}
+
+	p := spans[byName["parent"]]
+	for _, name := range []string{"redis get", "gorm.query"} {
+		s := spans[byName[name]]
+		if s.SpanContext.TraceID() != p.SpanContext.TraceID() {
+			t.Errorf("%s trace ID = %s, want parent's %s", name, s.SpanContext.TraceID(), p.SpanContext.TraceID())
+		}
+		if s.Parent.SpanID() != p.SpanContext.SpanID() {
+			t.Errorf("%s parent span ID = %s, want %s", name, s.Parent.SpanID(), p.SpanContext.SpanID())
+		}
+		if s.SpanKind != trace.SpanKindClient {
+			t.Errorf("%s span kind = %v, want client", name, s.SpanKind)
+		}
+	}
+	if got := spans[byName["redis get"]].Status.Code; got != codes.Error {
+		t.Errorf("redis span status = %v, want error", got)
+	}
+	attrs := make(map[string]string)
+	for _, kv := range spans[byName["gorm.query"]].Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["db.name"] != "greet" || attrs["db.sql.table"] != "greetings" || attrs["db.statement"] == "" {
+		t.Errorf("gorm span attributes = %v", attrs)
+	}
+}
+
+func TestInstrumentedCallsWithoutParentAreNotRecorded(t *testing.T) {
+	setupMemory(t)
+
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { _ = rdb.Close() })
+	rdb.AddHook(NewRedisHook("127.0.0.1:1"))
+	_ = rdb.Get(context.Background(), "greet:1").Err()
+
+	if spans := MemoryExporter().GetSpans(); len(spans) != 0 {
+		t.Errorf("recorded %d span(s) without a parent span", len(spans))
+	}
+}